@@ -0,0 +1,113 @@
+// Package blocks describes the blocks of Minecraft 1.7 by their numeric
+// ids and metadata, as sent in block changes and chunk sections.
+//
+// The block table is generated from blocks.txt by support/genblocks.
+package blocks
+
+//go:generate go run ../support/genblocks -o table.go blocks.txt
+
+// Tool is a class of tools digging some blocks faster.
+type Tool int
+
+const (
+	ToolNone Tool = iota
+	ToolPickaxe
+	ToolShovel
+	ToolAxe
+	ToolSword
+	ToolShears
+)
+
+// Shape is the kind of collision shape of a block.
+type Shape int
+
+const (
+	ShapeFull     Shape = iota // full cube
+	ShapeNone                  // no collision boxes
+	ShapeBox                   // single box independent of metadata
+	ShapeSlab                  // bottom or top half
+	ShapeStairs                // stairs, corners approximated
+	ShapeLadder                // thin box at the wall it is attached to
+	ShapeSnow                  // snow layers, 1-8 eighths of a block
+	ShapeTrapdoor              // trapdoor at the bottom or top, or at a side if open
+	ShapeGate                  // fence gate, passable if open
+	ShapeDoor                  // two blocks high door, turned a quarter if open
+	ShapeCake                  // cake with slices eaten
+)
+
+// Block describes a block.
+type Block struct {
+	ID          int
+	Name        string  // registry name without the "minecraft:" prefix
+	DisplayName string  // English name
+	Hardness    float64 // negative if unbreakable
+	Tool        Tool    // tool digging it faster
+	Level       int     // harvest level of Tool needed for drops, -1 if none
+	Light       int     // light emitted, 0-15
+	Solid       bool    // opaque full cube
+	Transparent bool    // lets light through
+	Replaceable bool    // placing a block replaces it
+	Interactive bool    // opens a window or changes state when clicked
+	Shape       Shape
+
+	box      []Box    // collision box of ShapeBox
+	mask     int      // metadata bits selecting the variant
+	variants []string // display names by variant
+}
+
+// Known reports if b is a block of Minecraft 1.7.
+func (b *Block) Known() bool { return b.Name != "" }
+
+// Variant returns the display name of b with metadata meta.
+func (b *Block) Variant(meta int) string {
+	if m := meta & b.mask; m < len(b.variants) && b.variants[m] != "" {
+		return b.variants[m]
+	}
+	return b.DisplayName
+}
+
+// unknown is returned for ids missing from the table. Unknown blocks
+// are treated as unbreakable full cubes.
+var unknown = Block{Hardness: -1, Level: -1, Solid: true}
+
+// ByID returns the block with id. Blocks not in Minecraft 1.7 are
+// reported as unbreakable solid cubes without a name. The block
+// returned must not be modified.
+func ByID(id int) *Block {
+	if id < 0 || id >= len(table) || !table[id].Known() {
+		b := unknown
+		b.ID = id
+		return &b
+	}
+	return &table[id]
+}
+
+var byName map[string]*Block
+
+func init() {
+	byName = make(map[string]*Block)
+	for i := range table {
+		if b := &table[i]; b.Known() {
+			byName[b.Name] = b
+		}
+	}
+}
+
+// ByName returns the block with registry name name,
+// with or without the "minecraft:" prefix.
+func ByName(name string) (*Block, bool) {
+	if len(name) > 10 && name[:10] == "minecraft:" {
+		name = name[10:]
+	}
+	b, ok := byName[name]
+	return b, ok
+}
+
+// IsWater reports if the block is water.
+func IsWater(id int) bool { return id == FlowingWater || id == Water }
+
+// IsLava reports if the block is lava.
+func IsLava(id int) bool { return id == FlowingLava || id == Lava }
+
+// IsClimbable reports if players can climb the block.
+func IsClimbable(id int) bool { return id == Ladder || id == Vine }
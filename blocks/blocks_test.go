@@ -0,0 +1,121 @@
+package blocks
+
+import "testing"
+
+func TestByID(t *testing.T) {
+	b := ByID(Obsidian)
+	if b.Name != "obsidian" || b.DisplayName != "Obsidian" || b.Hardness != 50 ||
+		b.Tool != ToolPickaxe || b.Level != 3 || !b.Solid || b.Transparent {
+		t.Errorf("obsidian: got %+v", *b)
+	}
+	if b := ByID(Glowstone); b.Light != 15 || !b.Solid {
+		t.Errorf("glowstone: got %+v", *b)
+	}
+	if b := ByID(PackedIce); b.Tool != ToolPickaxe || b.Level != -1 {
+		t.Errorf("packed ice: got %+v", *b)
+	}
+	if b := ByID(Glass); !b.Transparent || b.Solid {
+		t.Errorf("glass: got %+v", *b)
+	}
+	for _, id := range []int{-1, 165, 176, 4095} {
+		b := ByID(id)
+		if b.Known() || b.ID != id || b.Hardness >= 0 || !b.Solid || b.Shape != ShapeFull {
+			t.Errorf("unknown block %d: got %+v", id, *b)
+		}
+	}
+	for i := range table {
+		if b := &table[i]; b.Known() && b.ID != i {
+			t.Errorf("block %q at %d has id %d", b.Name, i, b.ID)
+		}
+	}
+}
+
+func TestByName(t *testing.T) {
+	for _, n := range []string{"ladder", "minecraft:ladder"} {
+		if b, ok := ByName(n); !ok || b.ID != Ladder {
+			t.Errorf("%s: got %v, %v", n, b, ok)
+		}
+	}
+	if _, ok := ByName("minecraft:nonexistent"); ok {
+		t.Error("nonexistent block found")
+	}
+}
+
+func TestVariant(t *testing.T) {
+	tests := []struct {
+		id, meta int
+		want     string
+	}{
+		{Stone, 0, "Stone"},
+		{Wool, 14, "Red Wool"},
+		{Log, 2, "Birch Wood"},
+		{Log, 2 | 8, "Birch Wood"}, // lying along the Z axis
+		{Leaves, 1 | 4, "Spruce Leaves"},
+		{StoneSlab, 3 | 8, "Cobblestone Slab"},
+		{QuartzBlock, 4, "Pillar Quartz Block"},
+		{DoublePlant, 6, "Sunflower"},
+		{Carpet, 0, "White Carpet"},
+	}
+	for _, tt := range tests {
+		if got := ByID(tt.id).Variant(tt.meta); got != tt.want {
+			t.Errorf("%d:%d: got %q, want %q", tt.id, tt.meta, got, tt.want)
+		}
+	}
+}
+
+func TestBoxes(t *testing.T) {
+	tests := []struct {
+		id, meta int
+		n        int
+		height   float64
+	}{
+		{Stone, 0, 1, 1},
+		{Air, 0, 0, 0},
+		{Tallgrass, 1, 0, 0},
+		{StoneSlab, 0, 1, 0.5},
+		{StoneSlab, 8, 1, 1},
+		{OakStairs, 2, 2, 1},
+		{SnowLayer, 0, 0, 0},
+		{SnowLayer, 3, 1, 0.375},
+		{Fence, 0, 1, 1.5},
+		{FenceGate, 4, 0, 0},
+		{WoodenDoor, 1, 1, 2},
+		{WoodenDoor, 1 | 4, 1, 2}, // open
+		{IronDoor, 8, 0, 0},       // upper half
+		{Trapdoor, 8, 1, 1},
+		{Trapdoor, 4 | 2, 1, 1}, // open
+		{Chest, 0, 1, 0.875},
+		{Carpet, 5, 1, 0.0625},
+		{165, 0, 1, 1},
+	}
+	for _, tt := range tests {
+		if n := len(Boxes(tt.id, tt.meta)); n != tt.n {
+			t.Errorf("%d:%d: got %d boxes, want %d", tt.id, tt.meta, n, tt.n)
+		}
+		if h := Height(tt.id, tt.meta); h != tt.height {
+			t.Errorf("%d:%d: got height %v, want %v", tt.id, tt.meta, h, tt.height)
+		}
+	}
+}
+
+func TestPanels(t *testing.T) {
+	tests := []struct {
+		id, meta int
+		want     Box
+	}{
+		{Trapdoor, 4 | 0, box(0, 0, 0.8125, 1, 1, 1)},
+		{Trapdoor, 4 | 1, box(0, 0, 0, 1, 1, 0.1875)},
+		{Trapdoor, 4 | 2, box(0.8125, 0, 0, 1, 1, 1)},
+		{Trapdoor, 4 | 3, box(0, 0, 0, 0.1875, 1, 1)},
+		{WoodenDoor, 0, box(0, 0, 0, 0.1875, 2, 1)},
+		{WoodenDoor, 4 | 0, box(0, 0, 0, 1, 2, 0.1875)},
+		{WoodenDoor, 4 | 1, box(0.8125, 0, 0, 1, 2, 1)},
+		{IronDoor, 4 | 2, box(0, 0, 0.8125, 1, 2, 1)},
+		{IronDoor, 4 | 3, box(0, 0, 0, 0.1875, 2, 1)},
+	}
+	for _, tt := range tests {
+		if v := Boxes(tt.id, tt.meta); len(v) != 1 || v[0] != tt.want {
+			t.Errorf("%d:%d: got %v, want %v", tt.id, tt.meta, v, tt.want)
+		}
+	}
+}
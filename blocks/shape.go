@@ -0,0 +1,140 @@
+package blocks
+
+// Box is an axis aligned box relative to the corner of a block with the
+// lowest coordinates. Index 0, 1 and 2 of Min and Max are the X, Y and
+// Z coordinates.
+type Box struct {
+	Min, Max [3]float64
+}
+
+func box(x0, y0, z0, x1, y1, z1 float64) Box {
+	return Box{[3]float64{x0, y0, z0}, [3]float64{x1, y1, z1}}
+}
+
+var fullBlock = []Box{box(0, 0, 0, 1, 1, 1)}
+
+// Boxes returns the collision boxes of block id with metadata meta.
+// Shapes that depend on neighbouring blocks (fences, panes, stair
+// corners) are approximated. The slice returned must not be modified.
+func Boxes(id, meta int) []Box {
+	b := ByID(id)
+	switch b.Shape {
+	case ShapeNone:
+		return nil
+	case ShapeBox:
+		return b.box
+	case ShapeSlab:
+		if meta&8 != 0 {
+			return []Box{box(0, 0.5, 0, 1, 1, 1)}
+		}
+		return []Box{box(0, 0, 0, 1, 0.5, 1)}
+	case ShapeStairs:
+		return stairBoxes(meta)
+	case ShapeLadder:
+		switch meta {
+		case 2:
+			return []Box{box(0, 0, 0.875, 1, 1, 1)}
+		case 3:
+			return []Box{box(0, 0, 0, 1, 1, 0.125)}
+		case 4:
+			return []Box{box(0.875, 0, 0, 1, 1, 1)}
+		case 5:
+			return []Box{box(0, 0, 0, 0.125, 1, 1)}
+		}
+		return nil
+	case ShapeSnow:
+		if h := float64(meta&7) * 0.125; h > 0 {
+			return []Box{box(0, 0, 0, 1, h, 1)}
+		}
+		return nil
+	case ShapeTrapdoor:
+		switch {
+		case meta&4 != 0:
+			// open, turned up against the side it is attached to
+			return panel([4]int{sideZMax, sideZMin, sideXMax, sideXMin}[meta&3], 1)
+		case meta&8 != 0:
+			return []Box{box(0, 1-panelThickness, 0, 1, 1, 1)}
+		}
+		return []Box{box(0, 0, 0, 1, panelThickness, 1)}
+	case ShapeGate:
+		if meta&4 != 0 {
+			return nil
+		}
+		return []Box{box(0, 0, 0, 1, 1.5, 1)}
+	case ShapeDoor:
+		// The lower half holds the box of the whole door, the upper
+		// half does not know if it is open. The hinge, stored in the
+		// upper half, is assumed to be on the left side.
+		if meta&8 != 0 {
+			return nil
+		}
+		if meta&4 != 0 {
+			return panel([4]int{sideZMin, sideXMax, sideZMax, sideXMin}[meta&3], 2)
+		}
+		return panel([4]int{sideXMin, sideZMin, sideXMax, sideZMax}[meta&3], 2)
+	case ShapeCake:
+		return []Box{box(0.0625+float64(meta)*0.125, 0, 0.0625, 0.9375, 0.4375, 0.9375)}
+	}
+	return fullBlock
+}
+
+// panelThickness is the thickness of doors and trapdoors.
+const panelThickness = 0.1875
+
+// Sides of a block.
+const (
+	sideXMin = iota
+	sideXMax
+	sideZMin
+	sideZMax
+)
+
+// panel returns the box of a door or trapdoor h high along side.
+func panel(side int, h float64) []Box {
+	const t = panelThickness
+	switch side {
+	case sideXMin:
+		return []Box{box(0, 0, 0, t, h, 1)}
+	case sideXMax:
+		return []Box{box(1-t, 0, 0, 1, h, 1)}
+	case sideZMin:
+		return []Box{box(0, 0, 0, 1, h, t)}
+	}
+	return []Box{box(0, 0, 1-t, 1, h, 1)}
+}
+
+// stairBoxes returns the boxes of a straight stair.
+func stairBoxes(meta int) []Box {
+	y0, y1 := 0.0, 0.5
+	if meta&4 != 0 {
+		y0, y1 = 0.5, 1
+	}
+	half := box(0, y0, 0, 1, y1, 1)
+	y0, y1 = 1-y1, 1-y0
+	switch meta & 3 {
+	case 0:
+		return []Box{half, box(0.5, y0, 0, 1, y1, 1)}
+	case 1:
+		return []Box{half, box(0, y0, 0, 0.5, y1, 1)}
+	case 2:
+		return []Box{half, box(0, y0, 0.5, 1, y1, 1)}
+	}
+	return []Box{half, box(0, y0, 0, 1, y1, 0.5)}
+}
+
+// Passable reports if a block has no collision boxes.
+func Passable(id, meta int) bool {
+	return len(Boxes(id, meta)) == 0
+}
+
+// Height returns the height of the top of the collision boxes
+// of a block, zero for passable blocks.
+func Height(id, meta int) float64 {
+	h := 0.0
+	for _, b := range Boxes(id, meta) {
+		if b.Max[1] > h {
+			h = b.Max[1]
+		}
+	}
+	return h
+}
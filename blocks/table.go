@@ -0,0 +1,2019 @@
+// Code generated by genblocks from blocks.txt; DO NOT EDIT.
+
+package blocks
+
+// Block ids.
+const (
+	Air                        = 0
+	Stone                      = 1
+	Grass                      = 2
+	Dirt                       = 3
+	Cobblestone                = 4
+	Planks                     = 5
+	Sapling                    = 6
+	Bedrock                    = 7
+	FlowingWater               = 8
+	Water                      = 9
+	FlowingLava                = 10
+	Lava                       = 11
+	Sand                       = 12
+	Gravel                     = 13
+	GoldOre                    = 14
+	IronOre                    = 15
+	CoalOre                    = 16
+	Log                        = 17
+	Leaves                     = 18
+	Sponge                     = 19
+	Glass                      = 20
+	LapisOre                   = 21
+	LapisBlock                 = 22
+	Dispenser                  = 23
+	Sandstone                  = 24
+	Noteblock                  = 25
+	Bed                        = 26
+	GoldenRail                 = 27
+	DetectorRail               = 28
+	StickyPiston               = 29
+	Web                        = 30
+	Tallgrass                  = 31
+	Deadbush                   = 32
+	Piston                     = 33
+	PistonHead                 = 34
+	Wool                       = 35
+	PistonExtension            = 36
+	YellowFlower               = 37
+	RedFlower                  = 38
+	BrownMushroom              = 39
+	RedMushroom                = 40
+	GoldBlock                  = 41
+	IronBlock                  = 42
+	DoubleStoneSlab            = 43
+	StoneSlab                  = 44
+	BrickBlock                 = 45
+	TNT                        = 46
+	Bookshelf                  = 47
+	MossyCobblestone           = 48
+	Obsidian                   = 49
+	Torch                      = 50
+	Fire                       = 51
+	MobSpawner                 = 52
+	OakStairs                  = 53
+	Chest                      = 54
+	RedstoneWire               = 55
+	DiamondOre                 = 56
+	DiamondBlock               = 57
+	CraftingTable              = 58
+	Wheat                      = 59
+	Farmland                   = 60
+	Furnace                    = 61
+	LitFurnace                 = 62
+	StandingSign               = 63
+	WoodenDoor                 = 64
+	Ladder                     = 65
+	Rail                       = 66
+	StoneStairs                = 67
+	WallSign                   = 68
+	Lever                      = 69
+	StonePressurePlate         = 70
+	IronDoor                   = 71
+	WoodenPressurePlate        = 72
+	RedstoneOre                = 73
+	LitRedstoneOre             = 74
+	UnlitRedstoneTorch         = 75
+	RedstoneTorch              = 76
+	StoneButton                = 77
+	SnowLayer                  = 78
+	Ice                        = 79
+	Snow                       = 80
+	Cactus                     = 81
+	Clay                       = 82
+	Reeds                      = 83
+	Jukebox                    = 84
+	Fence                      = 85
+	Pumpkin                    = 86
+	Netherrack                 = 87
+	SoulSand                   = 88
+	Glowstone                  = 89
+	Portal                     = 90
+	LitPumpkin                 = 91
+	Cake                       = 92
+	UnpoweredRepeater          = 93
+	PoweredRepeater            = 94
+	StainedGlass               = 95
+	Trapdoor                   = 96
+	MonsterEgg                 = 97
+	Stonebrick                 = 98
+	BrownMushroomBlock         = 99
+	RedMushroomBlock           = 100
+	IronBars                   = 101
+	GlassPane                  = 102
+	MelonBlock                 = 103
+	PumpkinStem                = 104
+	MelonStem                  = 105
+	Vine                       = 106
+	FenceGate                  = 107
+	BrickStairs                = 108
+	StoneBrickStairs           = 109
+	Mycelium                   = 110
+	Waterlily                  = 111
+	NetherBrick                = 112
+	NetherBrickFence           = 113
+	NetherBrickStairs          = 114
+	NetherWart                 = 115
+	EnchantingTable            = 116
+	BrewingStand               = 117
+	Cauldron                   = 118
+	EndPortal                  = 119
+	EndPortalFrame             = 120
+	EndStone                   = 121
+	DragonEgg                  = 122
+	RedstoneLamp               = 123
+	LitRedstoneLamp            = 124
+	DoubleWoodenSlab           = 125
+	WoodenSlab                 = 126
+	Cocoa                      = 127
+	SandstoneStairs            = 128
+	EmeraldOre                 = 129
+	EnderChest                 = 130
+	TripwireHook               = 131
+	Tripwire                   = 132
+	EmeraldBlock               = 133
+	SpruceStairs               = 134
+	BirchStairs                = 135
+	JungleStairs               = 136
+	CommandBlock               = 137
+	Beacon                     = 138
+	CobblestoneWall            = 139
+	FlowerPot                  = 140
+	Carrots                    = 141
+	Potatoes                   = 142
+	WoodenButton               = 143
+	Skull                      = 144
+	Anvil                      = 145
+	TrappedChest               = 146
+	LightWeightedPressurePlate = 147
+	HeavyWeightedPressurePlate = 148
+	UnpoweredComparator        = 149
+	PoweredComparator          = 150
+	DaylightDetector           = 151
+	RedstoneBlock              = 152
+	QuartzOre                  = 153
+	Hopper                     = 154
+	QuartzBlock                = 155
+	QuartzStairs               = 156
+	ActivatorRail              = 157
+	Dropper                    = 158
+	StainedHardenedClay        = 159
+	StainedGlassPane           = 160
+	Leaves2                    = 161
+	Log2                       = 162
+	AcaciaStairs               = 163
+	DarkOakStairs              = 164
+	HayBlock                   = 170
+	Carpet                     = 171
+	HardenedClay               = 172
+	CoalBlock                  = 173
+	PackedIce                  = 174
+	DoublePlant                = 175
+)
+
+var table = [...]Block{
+	Air: {
+		ID:          Air,
+		Name:        "air",
+		DisplayName: "Air",
+		Level:       -1,
+		Transparent: true,
+		Replaceable: true,
+		Shape:       ShapeNone,
+	},
+	Stone: {
+		ID:          Stone,
+		Name:        "stone",
+		DisplayName: "Stone",
+		Hardness:    1.5,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Solid:       true,
+	},
+	Grass: {
+		ID:          Grass,
+		Name:        "grass",
+		DisplayName: "Grass Block",
+		Hardness:    0.6,
+		Tool:        ToolShovel,
+		Level:       -1,
+		Solid:       true,
+	},
+	Dirt: {
+		ID:          Dirt,
+		Name:        "dirt",
+		DisplayName: "Dirt",
+		Hardness:    0.5,
+		Tool:        ToolShovel,
+		Level:       -1,
+		Solid:       true,
+		mask:        3,
+		variants: []string{
+			"Dirt",
+			"Coarse Dirt",
+			"Podzol",
+		},
+	},
+	Cobblestone: {
+		ID:          Cobblestone,
+		Name:        "cobblestone",
+		DisplayName: "Cobblestone",
+		Hardness:    2,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Solid:       true,
+	},
+	Planks: {
+		ID:          Planks,
+		Name:        "planks",
+		DisplayName: "Wood Planks",
+		Hardness:    2,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Solid:       true,
+		mask:        7,
+		variants: []string{
+			"Oak Wood Planks",
+			"Spruce Wood Planks",
+			"Birch Wood Planks",
+			"Jungle Wood Planks",
+			"Acacia Wood Planks",
+			"Dark Oak Wood Planks",
+		},
+	},
+	Sapling: {
+		ID:          Sapling,
+		Name:        "sapling",
+		DisplayName: "Sapling",
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeNone,
+		mask:        7,
+		variants: []string{
+			"Oak Sapling",
+			"Spruce Sapling",
+			"Birch Sapling",
+			"Jungle Sapling",
+			"Acacia Sapling",
+			"Dark Oak Sapling",
+		},
+	},
+	Bedrock: {
+		ID:          Bedrock,
+		Name:        "bedrock",
+		DisplayName: "Bedrock",
+		Hardness:    -1,
+		Level:       -1,
+		Solid:       true,
+	},
+	FlowingWater: {
+		ID:          FlowingWater,
+		Name:        "flowing_water",
+		DisplayName: "Water",
+		Hardness:    100,
+		Level:       -1,
+		Transparent: true,
+		Replaceable: true,
+		Shape:       ShapeNone,
+	},
+	Water: {
+		ID:          Water,
+		Name:        "water",
+		DisplayName: "Water",
+		Hardness:    100,
+		Level:       -1,
+		Transparent: true,
+		Replaceable: true,
+		Shape:       ShapeNone,
+	},
+	FlowingLava: {
+		ID:          FlowingLava,
+		Name:        "flowing_lava",
+		DisplayName: "Lava",
+		Hardness:    100,
+		Level:       -1,
+		Light:       15,
+		Replaceable: true,
+		Shape:       ShapeNone,
+	},
+	Lava: {
+		ID:          Lava,
+		Name:        "lava",
+		DisplayName: "Lava",
+		Hardness:    100,
+		Level:       -1,
+		Light:       15,
+		Replaceable: true,
+		Shape:       ShapeNone,
+	},
+	Sand: {
+		ID:          Sand,
+		Name:        "sand",
+		DisplayName: "Sand",
+		Hardness:    0.5,
+		Tool:        ToolShovel,
+		Level:       -1,
+		Solid:       true,
+		mask:        1,
+		variants: []string{
+			"Sand",
+			"Red Sand",
+		},
+	},
+	Gravel: {
+		ID:          Gravel,
+		Name:        "gravel",
+		DisplayName: "Gravel",
+		Hardness:    0.6,
+		Tool:        ToolShovel,
+		Level:       -1,
+		Solid:       true,
+	},
+	GoldOre: {
+		ID:          GoldOre,
+		Name:        "gold_ore",
+		DisplayName: "Gold Ore",
+		Hardness:    3,
+		Tool:        ToolPickaxe,
+		Level:       2,
+		Solid:       true,
+	},
+	IronOre: {
+		ID:          IronOre,
+		Name:        "iron_ore",
+		DisplayName: "Iron Ore",
+		Hardness:    3,
+		Tool:        ToolPickaxe,
+		Level:       1,
+		Solid:       true,
+	},
+	CoalOre: {
+		ID:          CoalOre,
+		Name:        "coal_ore",
+		DisplayName: "Coal Ore",
+		Hardness:    3,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Solid:       true,
+	},
+	Log: {
+		ID:          Log,
+		Name:        "log",
+		DisplayName: "Wood",
+		Hardness:    2,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Solid:       true,
+		mask:        3,
+		variants: []string{
+			"Oak Wood",
+			"Spruce Wood",
+			"Birch Wood",
+			"Jungle Wood",
+		},
+	},
+	Leaves: {
+		ID:          Leaves,
+		Name:        "leaves",
+		DisplayName: "Leaves",
+		Hardness:    0.2,
+		Tool:        ToolShears,
+		Level:       -1,
+		Transparent: true,
+		mask:        3,
+		variants: []string{
+			"Oak Leaves",
+			"Spruce Leaves",
+			"Birch Leaves",
+			"Jungle Leaves",
+		},
+	},
+	Sponge: {
+		ID:          Sponge,
+		Name:        "sponge",
+		DisplayName: "Sponge",
+		Hardness:    0.6,
+		Level:       -1,
+		Solid:       true,
+	},
+	Glass: {
+		ID:          Glass,
+		Name:        "glass",
+		DisplayName: "Glass",
+		Hardness:    0.3,
+		Level:       -1,
+		Transparent: true,
+	},
+	LapisOre: {
+		ID:          LapisOre,
+		Name:        "lapis_ore",
+		DisplayName: "Lapis Lazuli Ore",
+		Hardness:    3,
+		Tool:        ToolPickaxe,
+		Level:       1,
+		Solid:       true,
+	},
+	LapisBlock: {
+		ID:          LapisBlock,
+		Name:        "lapis_block",
+		DisplayName: "Lapis Lazuli Block",
+		Hardness:    3,
+		Tool:        ToolPickaxe,
+		Level:       1,
+		Solid:       true,
+	},
+	Dispenser: {
+		ID:          Dispenser,
+		Name:        "dispenser",
+		DisplayName: "Dispenser",
+		Hardness:    3.5,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Solid:       true,
+		Interactive: true,
+	},
+	Sandstone: {
+		ID:          Sandstone,
+		Name:        "sandstone",
+		DisplayName: "Sandstone",
+		Hardness:    0.8,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Solid:       true,
+		mask:        3,
+		variants: []string{
+			"Sandstone",
+			"Chiseled Sandstone",
+			"Smooth Sandstone",
+		},
+	},
+	Noteblock: {
+		ID:          Noteblock,
+		Name:        "noteblock",
+		DisplayName: "Note Block",
+		Hardness:    0.8,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Solid:       true,
+		Interactive: true,
+	},
+	Bed: {
+		ID:          Bed,
+		Name:        "bed",
+		DisplayName: "Bed",
+		Hardness:    0.2,
+		Level:       -1,
+		Transparent: true,
+		Interactive: true,
+		Shape:       ShapeBox,
+		box:         []Box{{Min: [3]float64{0, 0, 0}, Max: [3]float64{1, 0.5625, 1}}},
+	},
+	GoldenRail: {
+		ID:          GoldenRail,
+		Name:        "golden_rail",
+		DisplayName: "Powered Rail",
+		Hardness:    0.7,
+		Tool:        ToolPickaxe,
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	DetectorRail: {
+		ID:          DetectorRail,
+		Name:        "detector_rail",
+		DisplayName: "Detector Rail",
+		Hardness:    0.7,
+		Tool:        ToolPickaxe,
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	StickyPiston: {
+		ID:          StickyPiston,
+		Name:        "sticky_piston",
+		DisplayName: "Sticky Piston",
+		Hardness:    0.5,
+		Level:       -1,
+		Solid:       true,
+	},
+	Web: {
+		ID:          Web,
+		Name:        "web",
+		DisplayName: "Cobweb",
+		Hardness:    4,
+		Tool:        ToolShears,
+		Level:       0,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	Tallgrass: {
+		ID:          Tallgrass,
+		Name:        "tallgrass",
+		DisplayName: "Grass",
+		Level:       -1,
+		Transparent: true,
+		Replaceable: true,
+		Shape:       ShapeNone,
+		mask:        3,
+		variants: []string{
+			"Shrub",
+			"Grass",
+			"Fern",
+		},
+	},
+	Deadbush: {
+		ID:          Deadbush,
+		Name:        "deadbush",
+		DisplayName: "Dead Bush",
+		Level:       -1,
+		Transparent: true,
+		Replaceable: true,
+		Shape:       ShapeNone,
+	},
+	Piston: {
+		ID:          Piston,
+		Name:        "piston",
+		DisplayName: "Piston",
+		Hardness:    0.5,
+		Level:       -1,
+		Solid:       true,
+	},
+	PistonHead: {
+		ID:          PistonHead,
+		Name:        "piston_head",
+		DisplayName: "Piston Head",
+		Hardness:    0.5,
+		Level:       -1,
+		Transparent: true,
+	},
+	Wool: {
+		ID:          Wool,
+		Name:        "wool",
+		DisplayName: "Wool",
+		Hardness:    0.8,
+		Tool:        ToolShears,
+		Level:       -1,
+		Solid:       true,
+		mask:        15,
+		variants: []string{
+			"White Wool",
+			"Orange Wool",
+			"Magenta Wool",
+			"Light Blue Wool",
+			"Yellow Wool",
+			"Lime Wool",
+			"Pink Wool",
+			"Gray Wool",
+			"Light Gray Wool",
+			"Cyan Wool",
+			"Purple Wool",
+			"Blue Wool",
+			"Brown Wool",
+			"Green Wool",
+			"Red Wool",
+			"Black Wool",
+		},
+	},
+	PistonExtension: {
+		ID:          PistonExtension,
+		Name:        "piston_extension",
+		DisplayName: "Block moved by Piston",
+		Hardness:    -1,
+		Level:       -1,
+		Transparent: true,
+	},
+	YellowFlower: {
+		ID:          YellowFlower,
+		Name:        "yellow_flower",
+		DisplayName: "Dandelion",
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	RedFlower: {
+		ID:          RedFlower,
+		Name:        "red_flower",
+		DisplayName: "Poppy",
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeNone,
+		mask:        15,
+		variants: []string{
+			"Poppy",
+			"Blue Orchid",
+			"Allium",
+			"Azure Bluet",
+			"Red Tulip",
+			"Orange Tulip",
+			"White Tulip",
+			"Pink Tulip",
+			"Oxeye Daisy",
+		},
+	},
+	BrownMushroom: {
+		ID:          BrownMushroom,
+		Name:        "brown_mushroom",
+		DisplayName: "Mushroom",
+		Level:       -1,
+		Light:       1,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	RedMushroom: {
+		ID:          RedMushroom,
+		Name:        "red_mushroom",
+		DisplayName: "Mushroom",
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	GoldBlock: {
+		ID:          GoldBlock,
+		Name:        "gold_block",
+		DisplayName: "Block of Gold",
+		Hardness:    3,
+		Tool:        ToolPickaxe,
+		Level:       2,
+		Solid:       true,
+	},
+	IronBlock: {
+		ID:          IronBlock,
+		Name:        "iron_block",
+		DisplayName: "Block of Iron",
+		Hardness:    5,
+		Tool:        ToolPickaxe,
+		Level:       1,
+		Solid:       true,
+	},
+	DoubleStoneSlab: {
+		ID:          DoubleStoneSlab,
+		Name:        "double_stone_slab",
+		DisplayName: "Stone Slab",
+		Hardness:    2,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Solid:       true,
+		mask:        7,
+		variants: []string{
+			"Stone Slab",
+			"Sandstone Slab",
+			"Wooden Slab",
+			"Cobblestone Slab",
+			"Bricks Slab",
+			"Stone Bricks Slab",
+			"Nether Brick Slab",
+			"Quartz Slab",
+		},
+	},
+	StoneSlab: {
+		ID:          StoneSlab,
+		Name:        "stone_slab",
+		DisplayName: "Stone Slab",
+		Hardness:    2,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Shape:       ShapeSlab,
+		mask:        7,
+		variants: []string{
+			"Stone Slab",
+			"Sandstone Slab",
+			"Wooden Slab",
+			"Cobblestone Slab",
+			"Bricks Slab",
+			"Stone Bricks Slab",
+			"Nether Brick Slab",
+			"Quartz Slab",
+		},
+	},
+	BrickBlock: {
+		ID:          BrickBlock,
+		Name:        "brick_block",
+		DisplayName: "Bricks",
+		Hardness:    2,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Solid:       true,
+	},
+	TNT: {
+		ID:          TNT,
+		Name:        "tnt",
+		DisplayName: "TNT",
+		Level:       -1,
+		Solid:       true,
+	},
+	Bookshelf: {
+		ID:          Bookshelf,
+		Name:        "bookshelf",
+		DisplayName: "Bookshelf",
+		Hardness:    1.5,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Solid:       true,
+	},
+	MossyCobblestone: {
+		ID:          MossyCobblestone,
+		Name:        "mossy_cobblestone",
+		DisplayName: "Moss Stone",
+		Hardness:    2,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Solid:       true,
+	},
+	Obsidian: {
+		ID:          Obsidian,
+		Name:        "obsidian",
+		DisplayName: "Obsidian",
+		Hardness:    50,
+		Tool:        ToolPickaxe,
+		Level:       3,
+		Solid:       true,
+	},
+	Torch: {
+		ID:          Torch,
+		Name:        "torch",
+		DisplayName: "Torch",
+		Level:       -1,
+		Light:       14,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	Fire: {
+		ID:          Fire,
+		Name:        "fire",
+		DisplayName: "Fire",
+		Level:       -1,
+		Light:       15,
+		Transparent: true,
+		Replaceable: true,
+		Shape:       ShapeNone,
+	},
+	MobSpawner: {
+		ID:          MobSpawner,
+		Name:        "mob_spawner",
+		DisplayName: "Monster Spawner",
+		Hardness:    5,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Transparent: true,
+	},
+	OakStairs: {
+		ID:          OakStairs,
+		Name:        "oak_stairs",
+		DisplayName: "Oak Wood Stairs",
+		Hardness:    2,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Shape:       ShapeStairs,
+	},
+	Chest: {
+		ID:          Chest,
+		Name:        "chest",
+		DisplayName: "Chest",
+		Hardness:    2.5,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Transparent: true,
+		Interactive: true,
+		Shape:       ShapeBox,
+		box:         []Box{{Min: [3]float64{0.0625, 0, 0.0625}, Max: [3]float64{0.9375, 0.875, 0.9375}}},
+	},
+	RedstoneWire: {
+		ID:          RedstoneWire,
+		Name:        "redstone_wire",
+		DisplayName: "Redstone Dust",
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	DiamondOre: {
+		ID:          DiamondOre,
+		Name:        "diamond_ore",
+		DisplayName: "Diamond Ore",
+		Hardness:    3,
+		Tool:        ToolPickaxe,
+		Level:       2,
+		Solid:       true,
+	},
+	DiamondBlock: {
+		ID:          DiamondBlock,
+		Name:        "diamond_block",
+		DisplayName: "Block of Diamond",
+		Hardness:    5,
+		Tool:        ToolPickaxe,
+		Level:       2,
+		Solid:       true,
+	},
+	CraftingTable: {
+		ID:          CraftingTable,
+		Name:        "crafting_table",
+		DisplayName: "Crafting Table",
+		Hardness:    2.5,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Solid:       true,
+		Interactive: true,
+	},
+	Wheat: {
+		ID:          Wheat,
+		Name:        "wheat",
+		DisplayName: "Crops",
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	Farmland: {
+		ID:          Farmland,
+		Name:        "farmland",
+		DisplayName: "Farmland",
+		Hardness:    0.6,
+		Tool:        ToolShovel,
+		Level:       -1,
+	},
+	Furnace: {
+		ID:          Furnace,
+		Name:        "furnace",
+		DisplayName: "Furnace",
+		Hardness:    3.5,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Solid:       true,
+		Interactive: true,
+	},
+	LitFurnace: {
+		ID:          LitFurnace,
+		Name:        "lit_furnace",
+		DisplayName: "Furnace",
+		Hardness:    3.5,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Light:       13,
+		Solid:       true,
+		Interactive: true,
+	},
+	StandingSign: {
+		ID:          StandingSign,
+		Name:        "standing_sign",
+		DisplayName: "Sign",
+		Hardness:    1,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	WoodenDoor: {
+		ID:          WoodenDoor,
+		Name:        "wooden_door",
+		DisplayName: "Wooden Door",
+		Hardness:    3,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Transparent: true,
+		Interactive: true,
+		Shape:       ShapeDoor,
+	},
+	Ladder: {
+		ID:          Ladder,
+		Name:        "ladder",
+		DisplayName: "Ladder",
+		Hardness:    0.4,
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeLadder,
+	},
+	Rail: {
+		ID:          Rail,
+		Name:        "rail",
+		DisplayName: "Rail",
+		Hardness:    0.7,
+		Tool:        ToolPickaxe,
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	StoneStairs: {
+		ID:          StoneStairs,
+		Name:        "stone_stairs",
+		DisplayName: "Cobblestone Stairs",
+		Hardness:    2,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Shape:       ShapeStairs,
+	},
+	WallSign: {
+		ID:          WallSign,
+		Name:        "wall_sign",
+		DisplayName: "Sign",
+		Hardness:    1,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	Lever: {
+		ID:          Lever,
+		Name:        "lever",
+		DisplayName: "Lever",
+		Hardness:    0.5,
+		Level:       -1,
+		Transparent: true,
+		Interactive: true,
+		Shape:       ShapeNone,
+	},
+	StonePressurePlate: {
+		ID:          StonePressurePlate,
+		Name:        "stone_pressure_plate",
+		DisplayName: "Stone Pressure Plate",
+		Hardness:    0.5,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	IronDoor: {
+		ID:          IronDoor,
+		Name:        "iron_door",
+		DisplayName: "Iron Door",
+		Hardness:    5,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Transparent: true,
+		Interactive: true,
+		Shape:       ShapeDoor,
+	},
+	WoodenPressurePlate: {
+		ID:          WoodenPressurePlate,
+		Name:        "wooden_pressure_plate",
+		DisplayName: "Wooden Pressure Plate",
+		Hardness:    0.5,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	RedstoneOre: {
+		ID:          RedstoneOre,
+		Name:        "redstone_ore",
+		DisplayName: "Redstone Ore",
+		Hardness:    3,
+		Tool:        ToolPickaxe,
+		Level:       2,
+		Solid:       true,
+	},
+	LitRedstoneOre: {
+		ID:          LitRedstoneOre,
+		Name:        "lit_redstone_ore",
+		DisplayName: "Redstone Ore",
+		Hardness:    3,
+		Tool:        ToolPickaxe,
+		Level:       2,
+		Light:       9,
+		Solid:       true,
+	},
+	UnlitRedstoneTorch: {
+		ID:          UnlitRedstoneTorch,
+		Name:        "unlit_redstone_torch",
+		DisplayName: "Redstone Torch",
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	RedstoneTorch: {
+		ID:          RedstoneTorch,
+		Name:        "redstone_torch",
+		DisplayName: "Redstone Torch",
+		Level:       -1,
+		Light:       7,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	StoneButton: {
+		ID:          StoneButton,
+		Name:        "stone_button",
+		DisplayName: "Button",
+		Hardness:    0.5,
+		Level:       -1,
+		Transparent: true,
+		Interactive: true,
+		Shape:       ShapeNone,
+	},
+	SnowLayer: {
+		ID:          SnowLayer,
+		Name:        "snow_layer",
+		DisplayName: "Snow",
+		Hardness:    0.1,
+		Tool:        ToolShovel,
+		Level:       0,
+		Transparent: true,
+		Shape:       ShapeSnow,
+	},
+	Ice: {
+		ID:          Ice,
+		Name:        "ice",
+		DisplayName: "Ice",
+		Hardness:    0.5,
+		Tool:        ToolPickaxe,
+		Level:       -1,
+		Transparent: true,
+	},
+	Snow: {
+		ID:          Snow,
+		Name:        "snow",
+		DisplayName: "Snow",
+		Hardness:    0.2,
+		Tool:        ToolShovel,
+		Level:       0,
+		Solid:       true,
+	},
+	Cactus: {
+		ID:          Cactus,
+		Name:        "cactus",
+		DisplayName: "Cactus",
+		Hardness:    0.4,
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeBox,
+		box:         []Box{{Min: [3]float64{0.0625, 0, 0.0625}, Max: [3]float64{0.9375, 0.9375, 0.9375}}},
+	},
+	Clay: {
+		ID:          Clay,
+		Name:        "clay",
+		DisplayName: "Clay",
+		Hardness:    0.6,
+		Tool:        ToolShovel,
+		Level:       -1,
+		Solid:       true,
+	},
+	Reeds: {
+		ID:          Reeds,
+		Name:        "reeds",
+		DisplayName: "Sugar cane",
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	Jukebox: {
+		ID:          Jukebox,
+		Name:        "jukebox",
+		DisplayName: "Jukebox",
+		Hardness:    2,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Solid:       true,
+		Interactive: true,
+	},
+	Fence: {
+		ID:          Fence,
+		Name:        "fence",
+		DisplayName: "Fence",
+		Hardness:    2,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeBox,
+		box:         []Box{{Min: [3]float64{0, 0, 0}, Max: [3]float64{1, 1.5, 1}}},
+	},
+	Pumpkin: {
+		ID:          Pumpkin,
+		Name:        "pumpkin",
+		DisplayName: "Pumpkin",
+		Hardness:    1,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Solid:       true,
+	},
+	Netherrack: {
+		ID:          Netherrack,
+		Name:        "netherrack",
+		DisplayName: "Netherrack",
+		Hardness:    0.4,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Solid:       true,
+	},
+	SoulSand: {
+		ID:          SoulSand,
+		Name:        "soul_sand",
+		DisplayName: "Soul Sand",
+		Hardness:    0.5,
+		Tool:        ToolShovel,
+		Level:       -1,
+		Solid:       true,
+		Shape:       ShapeBox,
+		box:         []Box{{Min: [3]float64{0, 0, 0}, Max: [3]float64{1, 0.875, 1}}},
+	},
+	Glowstone: {
+		ID:          Glowstone,
+		Name:        "glowstone",
+		DisplayName: "Glowstone",
+		Hardness:    0.3,
+		Level:       -1,
+		Light:       15,
+		Solid:       true,
+	},
+	Portal: {
+		ID:          Portal,
+		Name:        "portal",
+		DisplayName: "Portal",
+		Hardness:    -1,
+		Level:       -1,
+		Light:       11,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	LitPumpkin: {
+		ID:          LitPumpkin,
+		Name:        "lit_pumpkin",
+		DisplayName: "Jack o'Lantern",
+		Hardness:    1,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Light:       15,
+		Solid:       true,
+	},
+	Cake: {
+		ID:          Cake,
+		Name:        "cake",
+		DisplayName: "Cake",
+		Hardness:    0.5,
+		Level:       -1,
+		Transparent: true,
+		Interactive: true,
+		Shape:       ShapeCake,
+	},
+	UnpoweredRepeater: {
+		ID:          UnpoweredRepeater,
+		Name:        "unpowered_repeater",
+		DisplayName: "Redstone Repeater",
+		Level:       -1,
+		Transparent: true,
+		Interactive: true,
+		Shape:       ShapeBox,
+		box:         []Box{{Min: [3]float64{0, 0, 0}, Max: [3]float64{1, 0.125, 1}}},
+	},
+	PoweredRepeater: {
+		ID:          PoweredRepeater,
+		Name:        "powered_repeater",
+		DisplayName: "Redstone Repeater",
+		Level:       -1,
+		Light:       9,
+		Transparent: true,
+		Interactive: true,
+		Shape:       ShapeBox,
+		box:         []Box{{Min: [3]float64{0, 0, 0}, Max: [3]float64{1, 0.125, 1}}},
+	},
+	StainedGlass: {
+		ID:          StainedGlass,
+		Name:        "stained_glass",
+		DisplayName: "Stained Glass",
+		Hardness:    0.3,
+		Level:       -1,
+		Transparent: true,
+		mask:        15,
+		variants: []string{
+			"White Stained Glass",
+			"Orange Stained Glass",
+			"Magenta Stained Glass",
+			"Light Blue Stained Glass",
+			"Yellow Stained Glass",
+			"Lime Stained Glass",
+			"Pink Stained Glass",
+			"Gray Stained Glass",
+			"Light Gray Stained Glass",
+			"Cyan Stained Glass",
+			"Purple Stained Glass",
+			"Blue Stained Glass",
+			"Brown Stained Glass",
+			"Green Stained Glass",
+			"Red Stained Glass",
+			"Black Stained Glass",
+		},
+	},
+	Trapdoor: {
+		ID:          Trapdoor,
+		Name:        "trapdoor",
+		DisplayName: "Trapdoor",
+		Hardness:    3,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Transparent: true,
+		Interactive: true,
+		Shape:       ShapeTrapdoor,
+	},
+	MonsterEgg: {
+		ID:          MonsterEgg,
+		Name:        "monster_egg",
+		DisplayName: "Stone Monster Egg",
+		Hardness:    0.75,
+		Level:       -1,
+		Solid:       true,
+		mask:        7,
+		variants: []string{
+			"Stone Monster Egg",
+			"Cobblestone Monster Egg",
+			"Stone Brick Monster Egg",
+			"Mossy Stone Brick Monster Egg",
+			"Cracked Stone Brick Monster Egg",
+			"Chiseled Stone Brick Monster Egg",
+		},
+	},
+	Stonebrick: {
+		ID:          Stonebrick,
+		Name:        "stonebrick",
+		DisplayName: "Stone Bricks",
+		Hardness:    1.5,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Solid:       true,
+		mask:        3,
+		variants: []string{
+			"Stone Bricks",
+			"Mossy Stone Bricks",
+			"Cracked Stone Bricks",
+			"Chiseled Stone Bricks",
+		},
+	},
+	BrownMushroomBlock: {
+		ID:          BrownMushroomBlock,
+		Name:        "brown_mushroom_block",
+		DisplayName: "Mushroom",
+		Hardness:    0.2,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Solid:       true,
+	},
+	RedMushroomBlock: {
+		ID:          RedMushroomBlock,
+		Name:        "red_mushroom_block",
+		DisplayName: "Mushroom",
+		Hardness:    0.2,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Solid:       true,
+	},
+	IronBars: {
+		ID:          IronBars,
+		Name:        "iron_bars",
+		DisplayName: "Iron Bars",
+		Hardness:    5,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Transparent: true,
+	},
+	GlassPane: {
+		ID:          GlassPane,
+		Name:        "glass_pane",
+		DisplayName: "Glass Pane",
+		Hardness:    0.3,
+		Level:       -1,
+		Transparent: true,
+	},
+	MelonBlock: {
+		ID:          MelonBlock,
+		Name:        "melon_block",
+		DisplayName: "Melon",
+		Hardness:    1,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Solid:       true,
+	},
+	PumpkinStem: {
+		ID:          PumpkinStem,
+		Name:        "pumpkin_stem",
+		DisplayName: "Pumpkin Stem",
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	MelonStem: {
+		ID:          MelonStem,
+		Name:        "melon_stem",
+		DisplayName: "Melon Stem",
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	Vine: {
+		ID:          Vine,
+		Name:        "vine",
+		DisplayName: "Vines",
+		Hardness:    0.2,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Transparent: true,
+		Replaceable: true,
+		Shape:       ShapeNone,
+	},
+	FenceGate: {
+		ID:          FenceGate,
+		Name:        "fence_gate",
+		DisplayName: "Fence Gate",
+		Hardness:    2,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Transparent: true,
+		Interactive: true,
+		Shape:       ShapeGate,
+	},
+	BrickStairs: {
+		ID:          BrickStairs,
+		Name:        "brick_stairs",
+		DisplayName: "Brick Stairs",
+		Hardness:    2,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Shape:       ShapeStairs,
+	},
+	StoneBrickStairs: {
+		ID:          StoneBrickStairs,
+		Name:        "stone_brick_stairs",
+		DisplayName: "Stone Brick Stairs",
+		Hardness:    1.5,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Shape:       ShapeStairs,
+	},
+	Mycelium: {
+		ID:          Mycelium,
+		Name:        "mycelium",
+		DisplayName: "Mycelium",
+		Hardness:    0.6,
+		Tool:        ToolShovel,
+		Level:       -1,
+		Solid:       true,
+	},
+	Waterlily: {
+		ID:          Waterlily,
+		Name:        "waterlily",
+		DisplayName: "Lily Pad",
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeBox,
+		box:         []Box{{Min: [3]float64{0, 0, 0}, Max: [3]float64{1, 0.015625, 1}}},
+	},
+	NetherBrick: {
+		ID:          NetherBrick,
+		Name:        "nether_brick",
+		DisplayName: "Nether Brick",
+		Hardness:    2,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Solid:       true,
+	},
+	NetherBrickFence: {
+		ID:          NetherBrickFence,
+		Name:        "nether_brick_fence",
+		DisplayName: "Nether Brick Fence",
+		Hardness:    2,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Transparent: true,
+		Shape:       ShapeBox,
+		box:         []Box{{Min: [3]float64{0, 0, 0}, Max: [3]float64{1, 1.5, 1}}},
+	},
+	NetherBrickStairs: {
+		ID:          NetherBrickStairs,
+		Name:        "nether_brick_stairs",
+		DisplayName: "Nether Brick Stairs",
+		Hardness:    2,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Shape:       ShapeStairs,
+	},
+	NetherWart: {
+		ID:          NetherWart,
+		Name:        "nether_wart",
+		DisplayName: "Nether Wart",
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	EnchantingTable: {
+		ID:          EnchantingTable,
+		Name:        "enchanting_table",
+		DisplayName: "Enchantment Table",
+		Hardness:    5,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Transparent: true,
+		Interactive: true,
+		Shape:       ShapeBox,
+		box:         []Box{{Min: [3]float64{0, 0, 0}, Max: [3]float64{1, 0.75, 1}}},
+	},
+	BrewingStand: {
+		ID:          BrewingStand,
+		Name:        "brewing_stand",
+		DisplayName: "Brewing Stand",
+		Hardness:    0.5,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Light:       1,
+		Transparent: true,
+		Interactive: true,
+	},
+	Cauldron: {
+		ID:          Cauldron,
+		Name:        "cauldron",
+		DisplayName: "Cauldron",
+		Hardness:    2,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Transparent: true,
+		Interactive: true,
+	},
+	EndPortal: {
+		ID:          EndPortal,
+		Name:        "end_portal",
+		DisplayName: "End Portal",
+		Hardness:    -1,
+		Level:       -1,
+		Light:       15,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	EndPortalFrame: {
+		ID:          EndPortalFrame,
+		Name:        "end_portal_frame",
+		DisplayName: "End Portal",
+		Hardness:    -1,
+		Level:       -1,
+		Light:       1,
+		Transparent: true,
+		Shape:       ShapeBox,
+		box:         []Box{{Min: [3]float64{0, 0, 0}, Max: [3]float64{1, 0.8125, 1}}},
+	},
+	EndStone: {
+		ID:          EndStone,
+		Name:        "end_stone",
+		DisplayName: "End Stone",
+		Hardness:    3,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Solid:       true,
+	},
+	DragonEgg: {
+		ID:          DragonEgg,
+		Name:        "dragon_egg",
+		DisplayName: "Dragon Egg",
+		Hardness:    3,
+		Level:       -1,
+		Light:       1,
+		Transparent: true,
+	},
+	RedstoneLamp: {
+		ID:          RedstoneLamp,
+		Name:        "redstone_lamp",
+		DisplayName: "Redstone Lamp",
+		Hardness:    0.3,
+		Level:       -1,
+		Solid:       true,
+	},
+	LitRedstoneLamp: {
+		ID:          LitRedstoneLamp,
+		Name:        "lit_redstone_lamp",
+		DisplayName: "Redstone Lamp",
+		Hardness:    0.3,
+		Level:       -1,
+		Light:       15,
+		Solid:       true,
+	},
+	DoubleWoodenSlab: {
+		ID:          DoubleWoodenSlab,
+		Name:        "double_wooden_slab",
+		DisplayName: "Wood Slab",
+		Hardness:    2,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Solid:       true,
+		mask:        7,
+		variants: []string{
+			"Oak Wood Slab",
+			"Spruce Wood Slab",
+			"Birch Wood Slab",
+			"Jungle Wood Slab",
+			"Acacia Wood Slab",
+			"Dark Oak Wood Slab",
+		},
+	},
+	WoodenSlab: {
+		ID:          WoodenSlab,
+		Name:        "wooden_slab",
+		DisplayName: "Wood Slab",
+		Hardness:    2,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Shape:       ShapeSlab,
+		mask:        7,
+		variants: []string{
+			"Oak Wood Slab",
+			"Spruce Wood Slab",
+			"Birch Wood Slab",
+			"Jungle Wood Slab",
+			"Acacia Wood Slab",
+			"Dark Oak Wood Slab",
+		},
+	},
+	Cocoa: {
+		ID:          Cocoa,
+		Name:        "cocoa",
+		DisplayName: "Cocoa",
+		Hardness:    0.2,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Transparent: true,
+	},
+	SandstoneStairs: {
+		ID:          SandstoneStairs,
+		Name:        "sandstone_stairs",
+		DisplayName: "Sandstone Stairs",
+		Hardness:    0.8,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Shape:       ShapeStairs,
+	},
+	EmeraldOre: {
+		ID:          EmeraldOre,
+		Name:        "emerald_ore",
+		DisplayName: "Emerald Ore",
+		Hardness:    3,
+		Tool:        ToolPickaxe,
+		Level:       2,
+		Solid:       true,
+	},
+	EnderChest: {
+		ID:          EnderChest,
+		Name:        "ender_chest",
+		DisplayName: "Ender Chest",
+		Hardness:    22.5,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Light:       7,
+		Transparent: true,
+		Interactive: true,
+		Shape:       ShapeBox,
+		box:         []Box{{Min: [3]float64{0.0625, 0, 0.0625}, Max: [3]float64{0.9375, 0.875, 0.9375}}},
+	},
+	TripwireHook: {
+		ID:          TripwireHook,
+		Name:        "tripwire_hook",
+		DisplayName: "Tripwire Hook",
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	Tripwire: {
+		ID:          Tripwire,
+		Name:        "tripwire",
+		DisplayName: "Tripwire",
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	EmeraldBlock: {
+		ID:          EmeraldBlock,
+		Name:        "emerald_block",
+		DisplayName: "Block of Emerald",
+		Hardness:    5,
+		Tool:        ToolPickaxe,
+		Level:       2,
+		Solid:       true,
+	},
+	SpruceStairs: {
+		ID:          SpruceStairs,
+		Name:        "spruce_stairs",
+		DisplayName: "Spruce Wood Stairs",
+		Hardness:    2,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Shape:       ShapeStairs,
+	},
+	BirchStairs: {
+		ID:          BirchStairs,
+		Name:        "birch_stairs",
+		DisplayName: "Birch Wood Stairs",
+		Hardness:    2,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Shape:       ShapeStairs,
+	},
+	JungleStairs: {
+		ID:          JungleStairs,
+		Name:        "jungle_stairs",
+		DisplayName: "Jungle Wood Stairs",
+		Hardness:    2,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Shape:       ShapeStairs,
+	},
+	CommandBlock: {
+		ID:          CommandBlock,
+		Name:        "command_block",
+		DisplayName: "Command Block",
+		Hardness:    -1,
+		Level:       -1,
+		Solid:       true,
+	},
+	Beacon: {
+		ID:          Beacon,
+		Name:        "beacon",
+		DisplayName: "Beacon",
+		Hardness:    3,
+		Level:       -1,
+		Light:       15,
+		Transparent: true,
+		Interactive: true,
+	},
+	CobblestoneWall: {
+		ID:          CobblestoneWall,
+		Name:        "cobblestone_wall",
+		DisplayName: "Cobblestone Wall",
+		Hardness:    2,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Transparent: true,
+		Shape:       ShapeBox,
+		box:         []Box{{Min: [3]float64{0, 0, 0}, Max: [3]float64{1, 1.5, 1}}},
+		mask:        1,
+		variants: []string{
+			"Cobblestone Wall",
+			"Mossy Cobblestone Wall",
+		},
+	},
+	FlowerPot: {
+		ID:          FlowerPot,
+		Name:        "flower_pot",
+		DisplayName: "Flower Pot",
+		Level:       -1,
+		Transparent: true,
+	},
+	Carrots: {
+		ID:          Carrots,
+		Name:        "carrots",
+		DisplayName: "Carrots",
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	Potatoes: {
+		ID:          Potatoes,
+		Name:        "potatoes",
+		DisplayName: "Potatoes",
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	WoodenButton: {
+		ID:          WoodenButton,
+		Name:        "wooden_button",
+		DisplayName: "Button",
+		Hardness:    0.5,
+		Level:       -1,
+		Transparent: true,
+		Interactive: true,
+		Shape:       ShapeNone,
+	},
+	Skull: {
+		ID:          Skull,
+		Name:        "skull",
+		DisplayName: "Head",
+		Hardness:    1,
+		Level:       -1,
+		Transparent: true,
+	},
+	Anvil: {
+		ID:          Anvil,
+		Name:        "anvil",
+		DisplayName: "Anvil",
+		Hardness:    5,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Transparent: true,
+		Interactive: true,
+	},
+	TrappedChest: {
+		ID:          TrappedChest,
+		Name:        "trapped_chest",
+		DisplayName: "Trapped Chest",
+		Hardness:    2.5,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Transparent: true,
+		Interactive: true,
+		Shape:       ShapeBox,
+		box:         []Box{{Min: [3]float64{0.0625, 0, 0.0625}, Max: [3]float64{0.9375, 0.875, 0.9375}}},
+	},
+	LightWeightedPressurePlate: {
+		ID:          LightWeightedPressurePlate,
+		Name:        "light_weighted_pressure_plate",
+		DisplayName: "Weighted Pressure Plate (Light)",
+		Hardness:    0.5,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	HeavyWeightedPressurePlate: {
+		ID:          HeavyWeightedPressurePlate,
+		Name:        "heavy_weighted_pressure_plate",
+		DisplayName: "Weighted Pressure Plate (Heavy)",
+		Hardness:    0.5,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	UnpoweredComparator: {
+		ID:          UnpoweredComparator,
+		Name:        "unpowered_comparator",
+		DisplayName: "Redstone Comparator",
+		Level:       -1,
+		Transparent: true,
+		Interactive: true,
+		Shape:       ShapeBox,
+		box:         []Box{{Min: [3]float64{0, 0, 0}, Max: [3]float64{1, 0.125, 1}}},
+	},
+	PoweredComparator: {
+		ID:          PoweredComparator,
+		Name:        "powered_comparator",
+		DisplayName: "Redstone Comparator",
+		Level:       -1,
+		Light:       9,
+		Transparent: true,
+		Interactive: true,
+		Shape:       ShapeBox,
+		box:         []Box{{Min: [3]float64{0, 0, 0}, Max: [3]float64{1, 0.125, 1}}},
+	},
+	DaylightDetector: {
+		ID:          DaylightDetector,
+		Name:        "daylight_detector",
+		DisplayName: "Daylight Sensor",
+		Hardness:    0.2,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeBox,
+		box:         []Box{{Min: [3]float64{0, 0, 0}, Max: [3]float64{1, 0.375, 1}}},
+	},
+	RedstoneBlock: {
+		ID:          RedstoneBlock,
+		Name:        "redstone_block",
+		DisplayName: "Block of Redstone",
+		Hardness:    5,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Solid:       true,
+	},
+	QuartzOre: {
+		ID:          QuartzOre,
+		Name:        "quartz_ore",
+		DisplayName: "Nether Quartz Ore",
+		Hardness:    3,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Solid:       true,
+	},
+	Hopper: {
+		ID:          Hopper,
+		Name:        "hopper",
+		DisplayName: "Hopper",
+		Hardness:    3,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Transparent: true,
+		Interactive: true,
+	},
+	QuartzBlock: {
+		ID:          QuartzBlock,
+		Name:        "quartz_block",
+		DisplayName: "Block of Quartz",
+		Hardness:    0.8,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Solid:       true,
+		mask:        7,
+		variants: []string{
+			"Block of Quartz",
+			"Chiseled Quartz Block",
+			"Pillar Quartz Block",
+			"Pillar Quartz Block",
+			"Pillar Quartz Block",
+		},
+	},
+	QuartzStairs: {
+		ID:          QuartzStairs,
+		Name:        "quartz_stairs",
+		DisplayName: "Quartz Stairs",
+		Hardness:    0.8,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Shape:       ShapeStairs,
+	},
+	ActivatorRail: {
+		ID:          ActivatorRail,
+		Name:        "activator_rail",
+		DisplayName: "Activator Rail",
+		Hardness:    0.7,
+		Tool:        ToolPickaxe,
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeNone,
+	},
+	Dropper: {
+		ID:          Dropper,
+		Name:        "dropper",
+		DisplayName: "Dropper",
+		Hardness:    3.5,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Solid:       true,
+		Interactive: true,
+	},
+	StainedHardenedClay: {
+		ID:          StainedHardenedClay,
+		Name:        "stained_hardened_clay",
+		DisplayName: "Stained Clay",
+		Hardness:    1.25,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Solid:       true,
+		mask:        15,
+		variants: []string{
+			"White Stained Clay",
+			"Orange Stained Clay",
+			"Magenta Stained Clay",
+			"Light Blue Stained Clay",
+			"Yellow Stained Clay",
+			"Lime Stained Clay",
+			"Pink Stained Clay",
+			"Gray Stained Clay",
+			"Light Gray Stained Clay",
+			"Cyan Stained Clay",
+			"Purple Stained Clay",
+			"Blue Stained Clay",
+			"Brown Stained Clay",
+			"Green Stained Clay",
+			"Red Stained Clay",
+			"Black Stained Clay",
+		},
+	},
+	StainedGlassPane: {
+		ID:          StainedGlassPane,
+		Name:        "stained_glass_pane",
+		DisplayName: "Stained Glass Pane",
+		Hardness:    0.3,
+		Level:       -1,
+		Transparent: true,
+		mask:        15,
+		variants: []string{
+			"White Stained Glass Pane",
+			"Orange Stained Glass Pane",
+			"Magenta Stained Glass Pane",
+			"Light Blue Stained Glass Pane",
+			"Yellow Stained Glass Pane",
+			"Lime Stained Glass Pane",
+			"Pink Stained Glass Pane",
+			"Gray Stained Glass Pane",
+			"Light Gray Stained Glass Pane",
+			"Cyan Stained Glass Pane",
+			"Purple Stained Glass Pane",
+			"Blue Stained Glass Pane",
+			"Brown Stained Glass Pane",
+			"Green Stained Glass Pane",
+			"Red Stained Glass Pane",
+			"Black Stained Glass Pane",
+		},
+	},
+	Leaves2: {
+		ID:          Leaves2,
+		Name:        "leaves2",
+		DisplayName: "Leaves",
+		Hardness:    0.2,
+		Tool:        ToolShears,
+		Level:       -1,
+		Transparent: true,
+		mask:        1,
+		variants: []string{
+			"Acacia Leaves",
+			"Dark Oak Leaves",
+		},
+	},
+	Log2: {
+		ID:          Log2,
+		Name:        "log2",
+		DisplayName: "Wood",
+		Hardness:    2,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Solid:       true,
+		mask:        1,
+		variants: []string{
+			"Acacia Wood",
+			"Dark Oak Wood",
+		},
+	},
+	AcaciaStairs: {
+		ID:          AcaciaStairs,
+		Name:        "acacia_stairs",
+		DisplayName: "Acacia Wood Stairs",
+		Hardness:    2,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Shape:       ShapeStairs,
+	},
+	DarkOakStairs: {
+		ID:          DarkOakStairs,
+		Name:        "dark_oak_stairs",
+		DisplayName: "Dark Oak Wood Stairs",
+		Hardness:    2,
+		Tool:        ToolAxe,
+		Level:       -1,
+		Shape:       ShapeStairs,
+	},
+	HayBlock: {
+		ID:          HayBlock,
+		Name:        "hay_block",
+		DisplayName: "Hay Bale",
+		Hardness:    0.5,
+		Level:       -1,
+		Solid:       true,
+	},
+	Carpet: {
+		ID:          Carpet,
+		Name:        "carpet",
+		DisplayName: "Carpet",
+		Hardness:    0.1,
+		Level:       -1,
+		Transparent: true,
+		Shape:       ShapeBox,
+		box:         []Box{{Min: [3]float64{0, 0, 0}, Max: [3]float64{1, 0.0625, 1}}},
+		mask:        15,
+		variants: []string{
+			"White Carpet",
+			"Orange Carpet",
+			"Magenta Carpet",
+			"Light Blue Carpet",
+			"Yellow Carpet",
+			"Lime Carpet",
+			"Pink Carpet",
+			"Gray Carpet",
+			"Light Gray Carpet",
+			"Cyan Carpet",
+			"Purple Carpet",
+			"Blue Carpet",
+			"Brown Carpet",
+			"Green Carpet",
+			"Red Carpet",
+			"Black Carpet",
+		},
+	},
+	HardenedClay: {
+		ID:          HardenedClay,
+		Name:        "hardened_clay",
+		DisplayName: "Hardened Clay",
+		Hardness:    1.25,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Solid:       true,
+	},
+	CoalBlock: {
+		ID:          CoalBlock,
+		Name:        "coal_block",
+		DisplayName: "Block of Coal",
+		Hardness:    5,
+		Tool:        ToolPickaxe,
+		Level:       0,
+		Solid:       true,
+	},
+	PackedIce: {
+		ID:          PackedIce,
+		Name:        "packed_ice",
+		DisplayName: "Packed Ice",
+		Hardness:    0.5,
+		Tool:        ToolPickaxe,
+		Level:       -1,
+		Solid:       true,
+	},
+	DoublePlant: {
+		ID:          DoublePlant,
+		Name:        "double_plant",
+		DisplayName: "Sunflower",
+		Level:       -1,
+		Transparent: true,
+		Replaceable: true,
+		Shape:       ShapeNone,
+		mask:        7,
+		variants: []string{
+			"Sunflower",
+			"Lilac",
+			"Double Tallgrass",
+			"Large Fern",
+			"Rose Bush",
+			"Peony",
+		},
+	},
+}
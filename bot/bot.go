@@ -0,0 +1,174 @@
+package bot
+
+import (
+	"context"
+	mcnet "github.com/tajtiattila/mctoy/net"
+	"github.com/tajtiattila/mctoy/pathfind"
+	"github.com/tajtiattila/mctoy/physics"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"github.com/tajtiattila/mctoy/world"
+)
+
+// Bot is a player controlled by a program. Its state is updated by
+// packet handlers and scheduler tasks, with the scheduler lock held.
+type Bot struct {
+	Sched *Scheduler
+
+	c *mcnet.Conn
+	q *mcnet.SendQueue
+
+	EntityID  int32
+	Spawned   bool // position received from the server
+	Position       // current position, sent at every tick
+	Sprinting bool
+
+	// World holds the blocks received from the server.
+	World *world.World
+
+	// Input is the movement simulated at each tick. Its Sneak and Sprint
+	// fields are ignored, Crouching and Sprinting are used instead.
+	Input physics.Input
+
+	// Finder computes the paths followed after GoTo.
+	Finder pathfind.Finder
+
+	// Inventory is the player inventory window.
+	Inventory Inventory
+
+	nav       *navigation         // path followed
+	dig       *digging            // block being dug
+	place     *placing            // block being placed
+	digDelay  int                 // ticks before digging may continue
+	breaking  map[uint]breakStage // blocks dug by other entities
+	effects   map[int8]int8       // potion effect amplifiers
+	body      physics.Player      // velocity and state of the simulation
+	move      Movement
+	crouching bool // sent with EntityAction
+	sprinting bool // sent with EntityAction
+}
+
+// New returns a Bot playing through c.
+// Packets are sent asynchronously through a SendQueue.
+func New(c *mcnet.Conn) *Bot {
+	b := &Bot{
+		Sched: NewScheduler(),
+		c:     c,
+		q:     mcnet.NewSendQueue(c, 64),
+		World: world.New(),
+
+		breaking: make(map[uint]breakStage),
+		effects:  make(map[int8]int8),
+	}
+	b.Finder.World = b.World
+	b.Sched.Every(1, b.tick)
+	return b
+}
+
+// Conn returns the connection of b.
+func (b *Bot) Conn() *mcnet.Conn { return b.c }
+
+// Send queues packet p to be sent to the server.
+func (b *Bot) Send(p interface{}) error {
+	return b.q.Send(p)
+}
+
+// Register adds the packet listeners of b to d.
+func (b *Bot) Register(d *mcnet.Dispatcher) {
+	d.On(b.joinGame)
+	d.On(b.respawn)
+	d.On(b.positionAndLook)
+	d.On(b.updateWorld)
+	d.On(b.setSlot)
+	d.On(b.windowItems)
+	d.On(b.heldItemChange)
+	d.On(b.entityEffect)
+	d.On(b.removeEntityEffect)
+	d.On(b.blockBreakAnimation)
+}
+
+// Run runs the scheduler of b until ctx is done, and returns ctx.Err()
+// after the packets queued are sent.
+func (b *Bot) Run(ctx context.Context) error {
+	err := b.Sched.Run(ctx)
+	b.q.Close()
+	return err
+}
+
+func (b *Bot) joinGame(c *mcnet.Conn, p *proto.JoinGame) error {
+	b.Sched.Lock()
+	defer b.Sched.Unlock()
+	b.EntityID = p.EntityID
+	b.World.Reset()
+	return nil
+}
+
+func (b *Bot) respawn(c *mcnet.Conn, p *proto.Respawn) error {
+	b.World.Reset()
+	return nil
+}
+
+// updateWorld applies chunk and block packets to the World of b.
+func (b *Bot) updateWorld(c *mcnet.Conn, p interface{}) error {
+	changed, err := b.World.Apply(p)
+	if !changed || err != nil {
+		return err
+	}
+	if v := world.BlockChanges(p); len(v) != 0 {
+		b.Sched.Lock()
+		defer b.Sched.Unlock()
+		for _, x := range v {
+			b.blockChanged(x.X, x.Y, x.Z)
+		}
+	}
+	return nil
+}
+
+func (b *Bot) positionAndLook(c *mcnet.Conn, p *proto.ServerPlayerPositionAndLook) error {
+	b.Sched.Lock()
+	defer b.Sched.Unlock()
+	b.Spawned = true
+	// acknowledge the correction before other movement packets
+	return b.Send(b.move.Correct(&b.Position, p))
+}
+
+// tick sends the movement packets of the tick.
+func (b *Bot) tick() {
+	if !b.Spawned {
+		return
+	}
+	b.navigate()
+	b.simulate()
+	b.digTick()
+	b.placeTick()
+	if b.Crouching != b.crouching {
+		b.crouching = b.Crouching
+		b.entityAction(ActionCrouch, ActionUncrouch, b.crouching)
+	}
+	if b.Sprinting != b.sprinting {
+		b.sprinting = b.Sprinting
+		b.entityAction(ActionStartSprint, ActionStopSprint, b.sprinting)
+	}
+	b.Send(b.move.Packet(b.Position))
+}
+
+func (b *Bot) entityAction(on, off int8, v bool) {
+	a := off
+	if v {
+		a = on
+	}
+	b.Send(proto.EntityAction{EntityID: b.EntityID, ActionID: a})
+}
+
+// simulate moves b by one tick according to Input,
+// unless the column it is in is not loaded yet.
+func (b *Bot) simulate() {
+	if !b.World.Loaded(floor(b.X), floor(b.Z)) {
+		return
+	}
+	p := &b.body
+	p.X, p.Y, p.Z, p.Yaw, p.OnGround = b.X, b.Y, b.Z, b.Yaw, b.OnGround
+	in := b.Input
+	in.Sneak, in.Sprint = b.Crouching, b.Sprinting
+	p.Step(b.World, in)
+	b.X, b.Y, b.Z, b.OnGround = p.X, p.Y, p.Z, p.OnGround
+}
@@ -0,0 +1,352 @@
+package bot
+
+import (
+	"errors"
+	"github.com/tajtiattila/mctoy/blocks"
+	mcnet "github.com/tajtiattila/mctoy/net"
+	"github.com/tajtiattila/mctoy/pathfind"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"math"
+)
+
+var (
+	ErrUnbreakable    = errors.New("bot: block can't be broken")
+	ErrOutOfReach     = errors.New("bot: block out of reach")
+	ErrDigCancelled   = errors.New("bot: digging cancelled")
+	ErrDigRejected    = errors.New("bot: server did not confirm block broken")
+	ErrBlockChanged   = errors.New("bot: block changed while digging")
+	ErrNothingToBreak = errors.New("bot: no block to break")
+)
+
+// PlayerDigging status values.
+const (
+	DigStart  = 0
+	DigCancel = 1
+	DigFinish = 2
+)
+
+// Block faces.
+const (
+	FaceBottom = iota
+	FaceTop
+	FaceNorth // -Z
+	FaceSouth // +Z
+	FaceWest  // -X
+	FaceEast  // +X
+)
+
+const (
+	// Reach is the distance from the eyes of the player
+	// within which blocks can be dug and placed.
+	Reach = 4.5
+
+	// digDelayTicks is the number of ticks after breaking
+	// a block before digging the next one starts.
+	digDelayTicks = 5
+
+	// digConfirmTicks is the number of ticks to wait for the
+	// server to confirm a block broken.
+	digConfirmTicks = 20
+
+	// swingAnimation is the ClientAnimation id of swinging the arm.
+	swingAnimation = 1
+)
+
+// digging is a block being dug.
+type digging struct {
+	pos      pathfind.Pos
+	id, meta int
+	face     int8
+	done     func(error)
+	started  bool
+	progress float64 // damage done, the block breaks at 1
+	finished int     // ticks since DigFinish was sent, -1 before
+}
+
+// Dig makes b dig the block at x, y, z. At the next tick b looks at the
+// block, selects the best tool from the hotbar, and starts digging. The
+// block is broken after the time the vanilla client would take, and
+// done, if not nil, is called from the scheduler when the server
+// confirms it with a BlockChange, or with the error if digging failed.
+// Digging a block cancels digging the previous one.
+func (b *Bot) Dig(x, y, z int, done func(error)) {
+	b.CancelDig()
+	p := pathfind.Pos{X: x, Y: y, Z: z}
+	id, meta := b.World.Block(x, y, z)
+	b.dig = &digging{pos: p, id: id, meta: meta, done: done, finished: -1}
+}
+
+// CancelDig stops digging. The done function of Dig is called
+// with ErrDigCancelled.
+func (b *Bot) CancelDig() {
+	if b.dig != nil {
+		b.finishDig(ErrDigCancelled)
+	}
+}
+
+// DigProgress returns the block being dug and the fraction of
+// the digging done.
+func (b *Bot) DigProgress() (p pathfind.Pos, progress float64, ok bool) {
+	if b.dig == nil {
+		return pathfind.Pos{}, 0, false
+	}
+	return b.dig.pos, math.Min(b.dig.progress, 1), true
+}
+
+// BreakStage returns the stage 0-9 of the block at x, y, z
+// being broken by another player, or -1 if it is not.
+func (b *Bot) BreakStage(x, y, z int) int {
+	for _, s := range b.breaking {
+		if s.pos == (pathfind.Pos{X: x, Y: y, Z: z}) {
+			return s.stage
+		}
+	}
+	return -1
+}
+
+type breakStage struct {
+	pos   pathfind.Pos
+	stage int
+}
+
+func (b *Bot) blockBreakAnimation(c *mcnet.Conn, p *proto.BlockBreakAnimation) error {
+	b.Sched.Lock()
+	defer b.Sched.Unlock()
+	if p.DestroyStage < 0 || p.DestroyStage > 9 {
+		delete(b.breaking, p.EntityID)
+	} else {
+		pos := pathfind.Pos{X: int(p.X), Y: int(p.Y), Z: int(p.Z)}
+		b.breaking[p.EntityID] = breakStage{pos, int(p.DestroyStage)}
+	}
+	return nil
+}
+
+// finishDig ends digging, telling the server if it was interrupted.
+func (b *Bot) finishDig(err error) {
+	d := b.dig
+	b.dig = nil
+	if d.started && d.finished < 0 {
+		b.sendDigging(DigCancel, d)
+	}
+	if d.done != nil {
+		d.done(err)
+	}
+}
+
+func (b *Bot) sendDigging(status int8, d *digging) {
+	b.Send(proto.PlayerDigging{
+		Status: status,
+		X:      int32(d.pos.X),
+		Y:      uint8(d.pos.Y),
+		Z:      int32(d.pos.Z),
+		Face:   d.face,
+	})
+}
+
+// digTick advances digging by a tick.
+func (b *Bot) digTick() {
+	d := b.dig
+	if d == nil {
+		if b.digDelay > 0 {
+			b.digDelay--
+		}
+		return
+	}
+	id, meta := b.World.Block(d.pos.X, d.pos.Y, d.pos.Z)
+	if d.finished >= 0 {
+		switch {
+		case id != d.id:
+			b.finishDig(nil)
+		case d.finished >= digConfirmTicks:
+			b.finishDig(ErrDigRejected)
+		default:
+			d.finished++
+		}
+		return
+	}
+	if id != d.id || meta != d.meta {
+		b.finishDig(ErrBlockChanged)
+		return
+	}
+	b.LookAt(float64(d.pos.X)+0.5, float64(d.pos.Y)+0.5, float64(d.pos.Z)+0.5)
+	if !d.started {
+		switch {
+		case id == blocks.Air || blocks.IsWater(id) || blocks.IsLava(id):
+			b.finishDig(ErrNothingToBreak)
+			return
+		case blocks.ByID(id).Hardness < 0:
+			b.finishDig(ErrUnbreakable)
+			return
+		case b.eyeDist(d.pos) > Reach:
+			b.finishDig(ErrOutOfReach)
+			return
+		}
+		b.Select(b.bestTool(id))
+		d.face = b.faceTowards(d.pos)
+		b.sendDigging(DigStart, d)
+		d.started = true
+	}
+	b.Send(proto.ClientAnimation{EntityID: b.EntityID, Animation: swingAnimation})
+	rate := b.breakRate(b.Inventory.HeldItem(), id)
+	if d.progress == 0 && rate >= 1 {
+		// broken by DigStart
+		d.progress, d.finished = 1, 0
+		return
+	}
+	if b.digDelay > 0 {
+		b.digDelay--
+		return
+	}
+	d.progress += rate
+	if d.progress >= 1 {
+		b.sendDigging(DigFinish, d)
+		d.finished = 0
+		b.digDelay = digDelayTicks
+	}
+}
+
+// breakRate returns the fraction of block id dug per tick
+// with item held, taking the state of b into account.
+func (b *Bot) breakRate(held proto.Slot, id int) float64 {
+	h := blocks.ByID(id).Hardness
+	switch {
+	case h < 0:
+		return 0
+	case h == 0:
+		return 1
+	}
+	speed := toolSpeed(int(held.Id), id)
+	if speed > 1 {
+		if eff := Enchantment(held, EnchantEfficiency); eff > 0 {
+			speed += float64(eff*eff + 1)
+		}
+	}
+	if a, ok := b.Effect(EffectHaste); ok {
+		speed *= 1 + float64(a+1)*0.2
+	}
+	if a, ok := b.Effect(EffectMiningFatigue); ok {
+		speed *= 1 - float64(a+1)*0.2
+	}
+	if b.eyesInWater() && Enchantment(b.Inventory.Slots[SlotHelmet], EnchantAquaAffinity) == 0 {
+		speed /= 5
+	}
+	if !b.OnGround {
+		speed /= 5
+	}
+	if canHarvest(int(held.Id), id) {
+		return speed / h / 30
+	}
+	return speed / h / 100
+}
+
+// DigTicks returns the number of ticks digging block id would
+// currently take with item held.
+func (b *Bot) DigTicks(held proto.Slot, id int) int {
+	r := b.breakRate(held, id)
+	if r <= 0 {
+		return -1
+	}
+	return int(math.Ceil(1 / r))
+}
+
+// bestTool returns the hotbar slot digging block id the fastest,
+// preferring the one selected.
+func (b *Bot) bestTool(id int) int {
+	best, rate := b.Inventory.Held, b.breakRate(b.Inventory.HeldItem(), id)
+	for i := 0; i < 9; i++ {
+		if r := b.breakRate(b.Inventory.Hotbar(i), id); r > rate {
+			best, rate = i, r
+		}
+	}
+	return best
+}
+
+// toolSpeed returns the digging speed of item on block id
+// without enchantments.
+func toolSpeed(item, id int) float64 {
+	t, ok := toolItems[item]
+	if !ok {
+		return 1
+	}
+	switch t.tool {
+	case blocks.ToolShears:
+		switch id {
+		case blocks.Leaves, blocks.Web, blocks.Leaves2:
+			return 15
+		case blocks.Wool:
+			return 5
+		}
+		return 1
+	case blocks.ToolSword:
+		switch id {
+		case blocks.Web:
+			return 15
+		case blocks.Leaves, blocks.Pumpkin, blocks.LitPumpkin, blocks.MelonBlock, blocks.Vine, blocks.Leaves2:
+			return 1.5
+		}
+		return 1
+	}
+	if blocks.ByID(id).Tool == t.tool {
+		return t.speed
+	}
+	return 1
+}
+
+// canHarvest reports if block id drops when dug with item.
+func canHarvest(item, id int) bool {
+	d := blocks.ByID(id)
+	if d.Level < 0 {
+		return true
+	}
+	t, ok := toolItems[item]
+	if !ok {
+		return false
+	}
+	if id == blocks.Web {
+		return t.tool == blocks.ToolShears || t.tool == blocks.ToolSword
+	}
+	return t.tool == d.Tool && t.level >= d.Level
+}
+
+// eyes returns the position of the eyes of b.
+func (b *Bot) eyes() (x, y, z float64) {
+	return b.X, b.Stance(), b.Z
+}
+
+func (b *Bot) eyesInWater() bool {
+	x, y, z := b.eyes()
+	id, _ := b.World.Block(floor(x), floor(y), floor(z))
+	return blocks.IsWater(id)
+}
+
+// eyeDist returns the distance of the center of block p from the eyes of b.
+func (b *Bot) eyeDist(p pathfind.Pos) float64 {
+	x, y, z := b.eyes()
+	dx, dy, dz := float64(p.X)+0.5-x, float64(p.Y)+0.5-y, float64(p.Z)+0.5-z
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+// faceTowards returns the face of block p towards the eyes of b.
+func (b *Bot) faceTowards(p pathfind.Pos) int8 {
+	x, y, z := b.eyes()
+	d := [3]float64{x - float64(p.X) - 0.5, y - float64(p.Y) - 0.5, z - float64(p.Z) - 0.5}
+	faces := [3][2]int8{{FaceWest, FaceEast}, {FaceBottom, FaceTop}, {FaceNorth, FaceSouth}}
+	axis := 0
+	for i := 1; i < 3; i++ {
+		if math.Abs(d[i]) > math.Abs(d[axis]) {
+			axis = i
+		}
+	}
+	if d[axis] < 0 {
+		return faces[axis][0]
+	}
+	return faces[axis][1]
+}
+
+// LookAt turns b to look at the point x, y, z.
+func (b *Bot) LookAt(x, y, z float64) {
+	ex, ey, ez := b.eyes()
+	dx, dy, dz := x-ex, y-ey, z-ez
+	b.Yaw = float32(math.Atan2(-dx, dz) * 180 / math.Pi)
+	b.Pitch = float32(-math.Atan2(dy, math.Hypot(dx, dz)) * 180 / math.Pi)
+}
@@ -0,0 +1,186 @@
+package bot
+
+import (
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/tajtiattila/mctoy/nbt"
+	mcnet "github.com/tajtiattila/mctoy/net"
+	proto "github.com/tajtiattila/mctoy/protocol"
+)
+
+// sentPackets records the packets sent by a test bot.
+type sentPackets struct {
+	mtx sync.Mutex
+	v   []interface{}
+}
+
+// newTestBot returns a spawned Bot standing on the floor of testWorld,
+// which records the packets it sends instead of sending them.
+func newTestBot(t *testing.T) (*Bot, *sentPackets) {
+	cc, sc := net.Pipe()
+	t.Cleanup(func() { cc.Close(); sc.Close() })
+	c := mcnet.NewConn(cc, proto.Client)
+	c.SetState(proto.StatePlay)
+	sent := new(sentPackets)
+	c.AddInterceptor(&mcnet.InterceptorFuncs{
+		Send: func(c *mcnet.Conn, p interface{}, next mcnet.SendFunc) error {
+			sent.mtx.Lock()
+			sent.v = append(sent.v, p)
+			sent.mtx.Unlock()
+			return nil
+		},
+	})
+	b := New(c)
+	b.World = testWorld()
+	b.Finder.World = b.World
+	b.EntityID = 42
+	b.Spawned = true
+	b.X, b.Y, b.Z, b.OnGround = 0.5, 64, 0.5, true
+	// let the bot settle on the ground
+	b.Sched.Tick()
+	b.Sched.Tick()
+	return b, sent
+}
+
+// packets flushes the send queue of b, and returns the packets sent
+// other than movement packets.
+func (s *sentPackets) packets(b *Bot) []interface{} {
+	b.q.Close()
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	var v []interface{}
+	for _, p := range s.v {
+		if mcnet.PacketPriority(p) != mcnet.PriorityMovement {
+			v = append(v, p)
+		}
+	}
+	return v
+}
+
+func enchanted(id uint16, ench, lvl int) proto.Slot {
+	t := nbt.Tag{Value: nbt.Compound{{Name: "ench", Value: &nbt.List{
+		Elem: nbt.TagCompound,
+		Values: []nbt.Value{nbt.Compound{
+			{Name: "id", Value: nbt.Short(ench)},
+			{Name: "lvl", Value: nbt.Short(lvl)},
+		}},
+	}}}}
+	b, err := nbt.EncodeTag(t)
+	if err == nil {
+		b, err = nbt.Compress(b, nbt.Gzip)
+	}
+	if err != nil {
+		panic(err)
+	}
+	return proto.Slot{Id: id, Count: 1, Tag: b}
+}
+
+func TestDigTicks(t *testing.T) {
+	b := &Bot{World: testWorld(), effects: make(map[int8]int8)}
+	b.X, b.Y, b.Z, b.OnGround = 0.5, 64, 0.5, true
+	hand := proto.Slot{Id: EmptyItem}
+	woodPick := proto.Slot{Id: 270, Count: 1}
+	diamondPick := proto.Slot{Id: 278, Count: 1}
+	tests := []struct {
+		item proto.Slot
+		id   int
+		want int
+	}{
+		{hand, 1, 150},
+		{woodPick, 1, 23},
+		{diamondPick, 1, 6},
+		{diamondPick, 49, 188},
+		{hand, 3, 15},
+		{woodPick, 3, 15},
+		{proto.Slot{Id: 269}, 3, 8},
+		{enchanted(278, EnchantEfficiency, 5), 1, 2},
+		{enchanted(278, EnchantEfficiency, 5), 3, 15}, // wrong tool
+		{enchanted(278, EnchantEfficiency, 5), 5, 60},
+		{proto.Slot{Id: 359}, 30, 8},
+		{hand, 7, -1},
+		{hand, 50, 1},
+		{woodPick, 79, 8},
+	}
+	for _, tt := range tests {
+		if got := b.DigTicks(tt.item, tt.id); got != tt.want {
+			t.Errorf("item %d block %d: got %d ticks, want %d", tt.item.Id, tt.id, got, tt.want)
+		}
+	}
+
+	b.OnGround = false
+	if got := b.DigTicks(diamondPick, 1); got != 29 {
+		t.Errorf("in air: got %d ticks, want 29", got)
+	}
+	b.OnGround = true
+	b.effects[EffectHaste] = 1
+	if got := b.DigTicks(woodPick, 1); got != 17 {
+		t.Errorf("haste II: got %d ticks, want 17", got)
+	}
+}
+
+func TestDig(t *testing.T) {
+	b, sent := newTestBot(t)
+	b.World.SetBlock(2, 64, 0, 1, 0)
+	b.Inventory.Slots[SlotHotbar+3] = proto.Slot{Id: 278, Count: 1}
+	b.Inventory.Slots[SlotHotbar+5] = proto.Slot{Id: 270, Count: 1}
+
+	var result error
+	done := false
+	b.Dig(2, 64, 0, func(err error) { result, done = err, true })
+	for i := 0; i < 6; i++ {
+		b.Sched.Tick()
+	}
+	if _, progress, ok := b.DigProgress(); !ok || progress < 1 || done {
+		t.Fatalf("progress %v after 6 ticks", progress)
+	}
+	b.Sched.Tick()
+	if done {
+		t.Fatal("done before block change")
+	}
+	b.updateWorld(nil, &proto.BlockChange{X: 2, Y: 64, Z: 0})
+	b.Sched.Tick()
+	if !done || result != nil {
+		t.Fatalf("done %v, err %v", done, result)
+	}
+
+	dig := func(status int8) proto.PlayerDigging {
+		return proto.PlayerDigging{Status: status, X: 2, Y: 64, Z: 0, Face: FaceWest}
+	}
+	swing := proto.ClientAnimation{EntityID: 42, Animation: 1}
+	want := []interface{}{
+		proto.ClientHeldItemChange{Slot: 3},
+		dig(DigStart),
+		swing, swing, swing, swing, swing, swing,
+		dig(DigFinish),
+	}
+	if got := sent.packets(b); !reflect.DeepEqual(got, want) {
+		t.Errorf("sent %v\nwant %v", got, want)
+	}
+}
+
+func TestDigCancel(t *testing.T) {
+	b, sent := newTestBot(t)
+	b.World.SetBlock(1, 64, 1, 1, 0)
+	var errs []error
+	b.Dig(1, 64, 1, func(err error) { errs = append(errs, err) })
+	b.Sched.Tick()
+	b.CancelDig()
+	b.Dig(0, 63, 0, func(err error) { errs = append(errs, err) })
+	for i := 0; i < 151+digConfirmTicks; i++ {
+		b.Sched.Tick()
+	}
+	b.Dig(0, 80, 0, func(err error) { errs = append(errs, err) })
+	b.Dig(9, 63, 9, func(err error) { errs = append(errs, err) })
+	b.Sched.Tick()
+	want := []error{ErrDigCancelled, ErrDigRejected, ErrDigCancelled, ErrOutOfReach}
+	if !reflect.DeepEqual(errs, want) {
+		t.Errorf("got %v, want %v", errs, want)
+	}
+	v := sent.packets(b)
+	if len(v) < 3 || v[2] != (proto.PlayerDigging{Status: DigCancel, X: 1, Y: 64, Z: 1, Face: FaceTop}) {
+		t.Errorf("cancel not sent: %v", v)
+	}
+}
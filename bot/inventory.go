@@ -0,0 +1,133 @@
+package bot
+
+import (
+	"github.com/tajtiattila/mctoy/nbt"
+	mcnet "github.com/tajtiattila/mctoy/net"
+	proto "github.com/tajtiattila/mctoy/protocol"
+)
+
+// Slots of the player inventory window.
+const (
+	SlotHelmet = 5
+	SlotHotbar = 36 // first hotbar slot
+	NumSlots   = 45
+)
+
+// EmptyItem is the item id of empty slots.
+const EmptyItem = 0xffff
+
+// Potion effect ids.
+const (
+	EffectHaste         = 3
+	EffectMiningFatigue = 4
+)
+
+// Enchantment ids.
+const (
+	EnchantAquaAffinity = 6
+	EnchantEfficiency   = 32
+)
+
+// Inventory is the player inventory window.
+type Inventory struct {
+	Slots [NumSlots]proto.Slot
+	Held  int // selected hotbar slot, 0-8
+}
+
+// HeldItem returns the item in the selected hotbar slot.
+func (inv *Inventory) HeldItem() proto.Slot {
+	return inv.Slots[SlotHotbar+inv.Held]
+}
+
+// Hotbar returns the item in hotbar slot i.
+func (inv *Inventory) Hotbar(i int) proto.Slot {
+	return inv.Slots[SlotHotbar+i]
+}
+
+// Select selects hotbar slot i, and tells the server if it has changed.
+func (b *Bot) Select(i int) {
+	if i == b.Inventory.Held {
+		return
+	}
+	b.Inventory.Held = i
+	b.Send(proto.ClientHeldItemChange{Slot: int16(i)})
+}
+
+// Effect returns the amplifier of potion effect id on b.
+func (b *Bot) Effect(id int) (amplifier int, ok bool) {
+	a, ok := b.effects[int8(id)]
+	return int(a), ok
+}
+
+// Enchantment returns the level of enchantment id on item s,
+// or zero if it has none.
+func Enchantment(s proto.Slot, id int) int {
+	if s.Id == EmptyItem || len(s.Tag) == 0 {
+		return 0
+	}
+	t, _, err := nbt.Read(s.Tag)
+	if err != nil {
+		return 0
+	}
+	v, err := nbt.Lookup(t.Value, "ench")
+	l, ok := v.(*nbt.List)
+	if err != nil || !ok {
+		return 0
+	}
+	for _, e := range l.Values {
+		c, ok := e.(nbt.Compound)
+		if !ok {
+			continue
+		}
+		if eid, ok := c.Get("id").(nbt.Short); ok && int(eid) == id {
+			lvl, _ := c.Get("lvl").(nbt.Short)
+			return int(lvl)
+		}
+	}
+	return 0
+}
+
+func (b *Bot) setSlot(c *mcnet.Conn, p *proto.SetSlot) error {
+	b.Sched.Lock()
+	defer b.Sched.Unlock()
+	if p.WindowID == 0 && 0 <= p.Slot && p.Slot < NumSlots {
+		b.Inventory.Slots[p.Slot] = p.SlotData
+	}
+	return nil
+}
+
+func (b *Bot) windowItems(c *mcnet.Conn, p *proto.WindowItems) error {
+	b.Sched.Lock()
+	defer b.Sched.Unlock()
+	if p.WindowID == 0 {
+		copy(b.Inventory.Slots[:], p.SlotData)
+	}
+	return nil
+}
+
+func (b *Bot) heldItemChange(c *mcnet.Conn, p *proto.ServerHeldItemChange) error {
+	b.Sched.Lock()
+	defer b.Sched.Unlock()
+	if 0 <= p.Slot && p.Slot < 9 {
+		b.Inventory.Held = int(p.Slot)
+	}
+	return nil
+}
+
+func (b *Bot) entityEffect(c *mcnet.Conn, p *proto.EntityEffect) error {
+	b.Sched.Lock()
+	defer b.Sched.Unlock()
+	if p.EntityID == b.EntityID {
+		b.effects[p.EffectID] = p.Amplifier
+	}
+	return nil
+}
+
+func (b *Bot) removeEntityEffect(c *mcnet.Conn, p *proto.RemoveEntityEffect) error {
+	b.Sched.Lock()
+	defer b.Sched.Unlock()
+	if p.EntityID == b.EntityID {
+		delete(b.effects, p.EffectID)
+	}
+	return nil
+}
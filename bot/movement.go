@@ -0,0 +1,113 @@
+package bot
+
+import (
+	proto "github.com/tajtiattila/mctoy/protocol"
+)
+
+const (
+	// EyeHeight is the height of the eyes of a player above its feet.
+	// Servers send the position of the eyes, clients that of the feet
+	// together with the stance, which is the height of the eyes.
+	EyeHeight = 1.62
+
+	// crouchEyeDrop is how much the eyes are lower when crouching.
+	crouchEyeDrop = 0.08
+
+	// fullUpdateTicks is the number of ticks after which
+	// the position is sent even if it is unchanged.
+	fullUpdateTicks = 20
+
+	// minMoveSq is the squared distance the player has to move
+	// for the position to be sent.
+	minMoveSq = 9e-4
+)
+
+// Entity action ids.
+const (
+	ActionCrouch      = 1
+	ActionUncrouch    = 2
+	ActionStartSprint = 4
+	ActionStopSprint  = 5
+)
+
+// Position is the state of the player sent in movement packets.
+type Position struct {
+	X, Y, Z    float64 // position of the feet
+	Yaw, Pitch float32 // in degrees
+	OnGround   bool
+	Crouching  bool
+}
+
+// Stance returns the height of the eyes of the player.
+func (p *Position) Stance() float64 {
+	if p.Crouching {
+		return p.Y + EyeHeight - crouchEyeDrop
+	}
+	return p.Y + EyeHeight
+}
+
+// Movement chooses the movement packet to send every tick like the
+// vanilla client: Player if nothing changed, PlayerPosition or PlayerLook
+// if only the position or the look changed, otherwise
+// ClientPlayerPositionAndLook. The position is sent at least every
+// 20 ticks.
+type Movement struct {
+	last  Position
+	valid bool // last is valid
+	ticks int  // since the position was sent
+}
+
+// Packet returns the movement packet to send for p, and records
+// it as the last state sent.
+func (m *Movement) Packet(p Position) interface{} {
+	dx, dy, dz := p.X-m.last.X, p.Y-m.last.Y, p.Z-m.last.Z
+	moved := !m.valid || dx*dx+dy*dy+dz*dz > minMoveSq ||
+		p.Stance() != m.last.Stance() || m.ticks+1 >= fullUpdateTicks
+	rotated := !m.valid || p.Yaw != m.last.Yaw || p.Pitch != m.last.Pitch
+
+	m.ticks++
+	if moved {
+		m.last.X, m.last.Y, m.last.Z, m.last.Crouching = p.X, p.Y, p.Z, p.Crouching
+		m.ticks = 0
+	}
+	if rotated {
+		m.last.Yaw, m.last.Pitch = p.Yaw, p.Pitch
+	}
+	m.last.OnGround = p.OnGround
+	m.valid = true
+
+	switch {
+	case moved && rotated:
+		return positionAndLook(&p)
+	case moved:
+		return proto.PlayerPosition{X: p.X, Y: p.Y, Stance: p.Stance(), Z: p.Z, OnGround: p.OnGround}
+	case rotated:
+		return proto.PlayerLook{Yaw: p.Yaw, Pitch: p.Pitch, OnGround: p.OnGround}
+	}
+	return proto.Player{OnGround: p.OnGround}
+}
+
+// Correct applies the position and look sent by the server to p,
+// and returns the packet acknowledging it, to be sent immediately.
+func (m *Movement) Correct(p *Position, s *proto.ServerPlayerPositionAndLook) interface{} {
+	p.X, p.Y, p.Z = s.X, s.Y-EyeHeight, s.Z
+	p.Yaw, p.Pitch = s.Yaw, s.Pitch
+	p.OnGround = s.OnGround
+	m.last, m.valid, m.ticks = *p, true, 0
+	ack := positionAndLook(p)
+	// acknowledge the exact height sent
+	ack.Stance = s.Y
+	return ack
+}
+
+func positionAndLook(p *Position) proto.ClientPlayerPositionAndLook {
+	return proto.ClientPlayerPositionAndLook{
+		X:        p.X,
+		Y:        p.Y,
+		Stance:   p.Stance(),
+		Z:        p.Z,
+		Yaw:      p.Yaw,
+		Pitch:    p.Pitch,
+		OnGround: p.OnGround,
+	}
+}
@@ -0,0 +1,75 @@
+package bot
+
+import (
+	"reflect"
+	"testing"
+
+	proto "github.com/tajtiattila/mctoy/protocol"
+)
+
+func TestMovementPacket(t *testing.T) {
+	var m Movement
+	p := Position{X: 10, Y: 64, Z: 10, OnGround: true}
+
+	check := func(what string, want interface{}) {
+		got := m.Packet(p)
+		if reflect.TypeOf(got) != reflect.TypeOf(want) {
+			t.Fatalf("%s: got %T, want %T", what, got, want)
+		}
+	}
+
+	check("first", proto.ClientPlayerPositionAndLook{})
+	check("still", proto.Player{})
+	p.X += 0.01
+	check("small move", proto.Player{})
+	p.X += 0.1
+	check("move", proto.PlayerPosition{})
+	p.Yaw = 90
+	check("look", proto.PlayerLook{})
+	p.Z++
+	p.Pitch = 10
+	check("move and look", proto.ClientPlayerPositionAndLook{})
+	p.Crouching = true
+	check("crouch", proto.PlayerPosition{})
+
+	for i := 1; i < fullUpdateTicks; i++ {
+		check("idle", proto.Player{})
+	}
+	check("full update", proto.PlayerPosition{})
+	check("after full update", proto.Player{})
+}
+
+func TestMovementStance(t *testing.T) {
+	var m Movement
+	p := Position{Y: 64}
+	pp := m.Packet(p).(proto.ClientPlayerPositionAndLook)
+	if pp.Y != 64 || pp.Stance != 64+EyeHeight {
+		t.Errorf("got Y %v stance %v", pp.Y, pp.Stance)
+	}
+	p.X, p.Crouching = 1, true
+	pos := m.Packet(p).(proto.PlayerPosition)
+	if want := 64 + EyeHeight - crouchEyeDrop; pos.Stance != want {
+		t.Errorf("crouching stance %v, want %v", pos.Stance, want)
+	}
+}
+
+func TestMovementCorrect(t *testing.T) {
+	var m Movement
+	p := Position{X: 1, Y: 2, Z: 3}
+	m.Packet(p)
+	s := &proto.ServerPlayerPositionAndLook{X: 5, Y: 65.62, Z: 7, Yaw: 45, Pitch: 5, OnGround: true}
+	ack := m.Correct(&p, s).(proto.ClientPlayerPositionAndLook)
+	want := proto.ClientPlayerPositionAndLook{
+		X: 5, Y: 65.62 - EyeHeight, Stance: 65.62, Z: 7,
+		Yaw: 45, Pitch: 5, OnGround: true,
+	}
+	if ack != want {
+		t.Errorf("ack %+v, want %+v", ack, want)
+	}
+	if p.X != 5 || p.Y != 65.62-EyeHeight || p.Z != 7 || p.Yaw != 45 {
+		t.Errorf("position not corrected: %+v", p)
+	}
+	if _, ok := m.Packet(p).(proto.Player); !ok {
+		t.Error("position sent again after acknowledging correction")
+	}
+}
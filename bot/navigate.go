@@ -0,0 +1,163 @@
+package bot
+
+import (
+	"errors"
+	"github.com/tajtiattila/mctoy/blocks"
+	"github.com/tajtiattila/mctoy/pathfind"
+	"github.com/tajtiattila/mctoy/physics"
+	"math"
+)
+
+// ErrStuck is reported when a bot fails to make progress along a path.
+var ErrStuck = errors.New("bot: stuck following path")
+
+const (
+	// stuckTicks is the number of ticks without reaching the
+	// next waypoint after which the path is recomputed.
+	stuckTicks = 60
+
+	// maxReplans is the number of times a path is recomputed
+	// because of getting stuck before giving up.
+	maxReplans = 3
+
+	// waypointRadius is the horizontal distance from the center
+	// of a waypoint within which it is reached.
+	waypointRadius = 0.3
+)
+
+// navigation is a path being followed.
+type navigation struct {
+	goal    pathfind.Pos
+	done    func(error)
+	path    []pathfind.Pos
+	next    int                   // index of the waypoint being approached
+	partial bool                  // path ends short of goal
+	blocks  map[pathfind.Pos]bool // blocks the path depends on
+	replan  bool                  // path to be recomputed
+	stuck   int                   // ticks since a waypoint was reached
+	replans int                   // since the last waypoint
+}
+
+// GoTo makes b walk to the block goal, replacing the path being followed.
+// The path is computed at the next tick using Finder, and is recomputed
+// when blocks along it change. If done is not nil, it is called
+// from the scheduler when goal is reached, or with the error if
+// it is unreachable.
+func (b *Bot) GoTo(goal pathfind.Pos, done func(error)) {
+	b.StopPath()
+	b.nav = &navigation{goal: goal, done: done, replan: true}
+}
+
+// StopPath stops following the path set by GoTo. The done function
+// of GoTo is not called.
+func (b *Bot) StopPath() {
+	if b.nav != nil {
+		b.nav = nil
+		b.Input = physics.Input{}
+	}
+}
+
+// Path returns the path being followed, and the index of the waypoint
+// b is heading to.
+func (b *Bot) Path() ([]pathfind.Pos, int) {
+	if b.nav == nil {
+		return nil, 0
+	}
+	return b.nav.path, b.nav.next
+}
+
+// BlockPos returns the block the feet of b are in.
+func (b *Bot) BlockPos() pathfind.Pos {
+	return pathfind.Pos{X: floor(b.X), Y: floor(b.Y), Z: floor(b.Z)}
+}
+
+// blockChanged makes the path recomputed if it depends on the block x, y, z.
+func (b *Bot) blockChanged(x, y, z int) {
+	if b.nav != nil && b.nav.blocks[pathfind.Pos{X: x, Y: y, Z: z}] {
+		b.nav.replan = true
+	}
+}
+
+func (b *Bot) finishPath(err error) {
+	done := b.nav.done
+	b.StopPath()
+	if done != nil {
+		done(err)
+	}
+}
+
+// plan computes the path of n, and reports if it succeeded.
+func (b *Bot) plan(n *navigation) bool {
+	n.replan = false
+	v, err := b.Finder.Find(b.BlockPos(), n.goal)
+	n.partial = err == pathfind.ErrBudget && len(v) > 1
+	if err != nil && !n.partial {
+		b.finishPath(err)
+		return false
+	}
+	n.path, n.next, n.stuck = v, 1, 0
+	n.blocks = make(map[pathfind.Pos]bool)
+	for _, p := range v {
+		for dy := -1; dy <= 2; dy++ {
+			n.blocks[pathfind.Pos{X: p.X, Y: p.Y + dy, Z: p.Z}] = true
+		}
+	}
+	return true
+}
+
+// navigate sets Input and Yaw to move b along its path.
+func (b *Bot) navigate() {
+	n := b.nav
+	if n == nil || (n.replan && !b.plan(n)) {
+		return
+	}
+	for n.next < len(n.path) && b.reached(n.path[n.next]) {
+		n.next++
+		n.stuck, n.replans = 0, 0
+	}
+	if n.next == len(n.path) {
+		if n.partial {
+			n.replan = true
+		} else {
+			b.finishPath(nil)
+		}
+		return
+	}
+	if n.stuck++; n.stuck > stuckTicks {
+		if n.replans++; n.replans > maxReplans {
+			b.finishPath(ErrStuck)
+			return
+		}
+		if !b.plan(n) {
+			return
+		}
+	}
+
+	w := n.path[n.next]
+	dx, dz := float64(w.X)+0.5-b.X, float64(w.Z)+0.5-b.Z
+	in := physics.Input{Forward: 1}
+	id, meta := b.World.Block(floor(b.X), floor(b.Y), floor(b.Z))
+	water := blocks.IsWater(id)
+	if yaw, ok := physics.LadderFacing(meta); ok && id == blocks.Ladder && w.Y > floor(b.Y) {
+		// climb by pushing against the wall
+		b.Yaw = yaw
+	} else {
+		if dx*dx+dz*dz < 0.1*0.1 {
+			in.Forward = 0
+		}
+		b.Yaw = float32(math.Atan2(-dx, dz) * 180 / math.Pi)
+	}
+	in.Jump = w.Y > floor(b.Y) || (water && w.Y >= floor(b.Y))
+	b.Input = in
+}
+
+// reached reports if b is at waypoint w.
+func (b *Bot) reached(w pathfind.Pos) bool {
+	dx, dz := float64(w.X)+0.5-b.X, float64(w.Z)+0.5-b.Z
+	dy := b.Y - float64(w.Y)
+	return dx*dx+dz*dz < waypointRadius*waypointRadius && dy > -0.6 && dy < 1
+}
+
+func floor(v float64) int {
+	return int(math.Floor(v))
+}
@@ -0,0 +1,135 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/tajtiattila/mctoy/pathfind"
+	"github.com/tajtiattila/mctoy/physics"
+	"github.com/tajtiattila/mctoy/world"
+)
+
+// testWorld returns a world with a stone floor at y = 63 in
+// the column at 0, 0.
+func testWorld() *world.World {
+	w := world.New()
+	c := &world.Column{Full: true}
+	for x := 0; x < 16; x++ {
+		for z := 0; z < 16; z++ {
+			c.SetBlock(x, 63, z, 1, 0)
+		}
+	}
+	w.Update(c)
+	return w
+}
+
+// walk runs the navigation and physics of b for at most n ticks,
+// and returns the error GoTo finished with.
+func walk(t *testing.T, b *Bot, goal pathfind.Pos, n int) error {
+	var result error
+	done := false
+	b.GoTo(goal, func(err error) {
+		result, done = err, true
+	})
+	for i := 0; i < n && !done; i++ {
+		b.navigate()
+		b.simulate()
+	}
+	if !done {
+		t.Fatalf("goal %v not reached, at %.2f %.2f %.2f", goal, b.X, b.Y, b.Z)
+	}
+	return result
+}
+
+func TestNavigate(t *testing.T) {
+	w := testWorld()
+	// wall with a step up, and a platform to drop from
+	for z := 0; z < 14; z++ {
+		w.SetBlock(5, 64, z, 1, 0)
+		w.SetBlock(5, 65, z, 1, 0)
+	}
+	w.SetBlock(5, 64, 14, 1, 0)
+	for x := 9; x < 12; x++ {
+		w.SetBlock(x, 64, 2, 1, 0)
+	}
+
+	b := &Bot{World: w, Spawned: true}
+	b.Finder.World = w
+	b.X, b.Y, b.Z, b.OnGround = 1.5, 64, 1.5, true
+
+	if err := walk(t, b, pathfind.Pos{X: 10, Y: 64, Z: 1}, 400); err != nil {
+		t.Fatal(err)
+	}
+	if p := b.BlockPos(); p != (pathfind.Pos{X: 10, Y: 64, Z: 1}) {
+		t.Errorf("arrived at %v", p)
+	}
+
+	if err := walk(t, b, pathfind.Pos{X: 10, Y: 65, Z: 2}, 200); err != nil {
+		t.Fatal(err)
+	}
+	if err := walk(t, b, pathfind.Pos{X: 10, Y: 64, Z: 6}, 200); err != nil {
+		t.Fatal(err)
+	}
+	if b.Input != (physics.Input{}) {
+		t.Errorf("input %+v left after arrival", b.Input)
+	}
+}
+
+func TestNavigateReplan(t *testing.T) {
+	w := testWorld()
+	b := &Bot{World: w, Spawned: true}
+	b.Finder.World = w
+	b.X, b.Y, b.Z, b.OnGround = 1.5, 64, 1.5, true
+
+	var result error
+	done := false
+	b.GoTo(pathfind.Pos{X: 1, Y: 64, Z: 12}, func(err error) {
+		result, done = err, true
+	})
+	b.navigate()
+	b.simulate()
+	path, _ := b.Path()
+	if len(path) == 0 {
+		t.Fatal("no path")
+	}
+	// wall off the straight path
+	for x := 0; x < 4; x++ {
+		for y := 64; y < 66; y++ {
+			w.SetBlock(x, y, 6, 1, 0)
+			b.blockChanged(x, y, 6)
+		}
+	}
+	if !b.nav.replan {
+		t.Fatal("block change did not invalidate the path")
+	}
+	b.navigate()
+	path, _ = b.Path()
+	for _, p := range path {
+		if p.Z == 6 && p.X < 4 {
+			t.Fatalf("replanned path %v goes through the wall", path)
+		}
+	}
+	b.simulate()
+	for i := 0; i < 600 && !done; i++ {
+		b.navigate()
+		b.simulate()
+	}
+	if !done {
+		t.Fatalf("goal not reached after replanning, at %.2f %.2f %.2f", b.X, b.Y, b.Z)
+	}
+	if result != nil {
+		t.Fatal(result)
+	}
+
+	// goal enclosed
+	for x := 0; x < 3; x++ {
+		for z := 0; z < 3; z++ {
+			if x != 1 || z != 1 {
+				w.SetBlock(x+8, 64, z+8, 1, 0)
+				w.SetBlock(x+8, 65, z+8, 1, 0)
+			}
+		}
+	}
+	if err := walk(t, b, pathfind.Pos{X: 9, Y: 64, Z: 9}, 10); err != pathfind.ErrNoPath {
+		t.Errorf("got %v, want ErrNoPath", err)
+	}
+}
@@ -0,0 +1,173 @@
+package bot
+
+import (
+	"errors"
+	"github.com/tajtiattila/mctoy/blocks"
+	"github.com/tajtiattila/mctoy/pathfind"
+	"github.com/tajtiattila/mctoy/physics"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"math"
+)
+
+var (
+	ErrNoItem         = errors.New("bot: item not in hotbar")
+	ErrOccupied       = errors.New("bot: block to place is occupied")
+	ErrNoSupport      = errors.New("bot: no block in reach to place against")
+	ErrObstructed     = errors.New("bot: player in the way of block placed")
+	ErrPlaceCancelled = errors.New("bot: placement cancelled")
+	ErrPlaceRejected  = errors.New("bot: server did not confirm block placed")
+)
+
+// placeConfirmTicks is the number of ticks to wait for the
+// server to confirm a block placed.
+const placeConfirmTicks = 20
+
+// faceDirs are the directions of faces, indexed by face.
+var faceDirs = [6]pathfind.Pos{
+	{X: 0, Y: -1, Z: 0},
+	{X: 0, Y: 1, Z: 0},
+	{X: 0, Y: 0, Z: -1},
+	{X: 0, Y: 0, Z: 1},
+	{X: -1, Y: 0, Z: 0},
+	{X: 1, Y: 0, Z: 0},
+}
+
+// placing is a block being placed.
+type placing struct {
+	target pathfind.Pos
+	item   int
+	done   func(error)
+	id     int // block at target when the packet was sent
+	sent   int // ticks since the packet was sent, -1 before
+}
+
+// PlaceBlock makes b place item at target. At the next tick b selects
+// the item in the hotbar, and clicks the face of a block next to target
+// within reach, looking at it. Blocks opening a window when clicked are
+// not used. If done is not nil, it is called from the scheduler when
+// the server confirms the block changed, or with the error if placing
+// failed. Placing a block cancels placing the previous one.
+func (b *Bot) PlaceBlock(target pathfind.Pos, item int, done func(error)) {
+	if b.place != nil {
+		b.finishPlace(ErrPlaceCancelled)
+	}
+	b.place = &placing{target: target, item: item, done: done, sent: -1}
+}
+
+func (b *Bot) finishPlace(err error) {
+	pl := b.place
+	b.place = nil
+	if pl.done != nil {
+		pl.done(err)
+	}
+}
+
+// placeTick advances placing by a tick.
+func (b *Bot) placeTick() {
+	pl := b.place
+	if pl == nil {
+		return
+	}
+	t := pl.target
+	if pl.sent >= 0 {
+		id, _ := b.World.Block(t.X, t.Y, t.Z)
+		switch {
+		case id != pl.id:
+			b.finishPlace(nil)
+		case pl.sent >= placeConfirmTicks:
+			b.finishPlace(ErrPlaceRejected)
+		default:
+			pl.sent++
+		}
+		return
+	}
+
+	slot := b.hotbarSlot(pl.item)
+	id, meta := b.World.Block(t.X, t.Y, t.Z)
+	placed, known := placedBlock(pl.item)
+	var err error
+	switch {
+	case slot < 0:
+		err = ErrNoItem
+	case !replaceable(id, meta):
+		err = ErrOccupied
+	case known && !blocks.Passable(placed, 0) && b.occupies(t):
+		err = ErrObstructed
+	}
+	if err != nil {
+		b.finishPlace(err)
+		return
+	}
+	against, face, ok := b.supportFace(t)
+	if !ok {
+		b.finishPlace(ErrNoSupport)
+		return
+	}
+
+	b.Select(slot)
+	// click the center of the face
+	d := faceDirs[face]
+	cx, cy, cz := 0.5+float64(d.X)/2, 0.5+float64(d.Y)/2, 0.5+float64(d.Z)/2
+	b.LookAt(float64(against.X)+cx, float64(against.Y)+cy, float64(against.Z)+cz)
+	b.Send(proto.PlayerBlockPlacement{
+		X:               int32(against.X),
+		Y:               uint8(against.Y),
+		Z:               int32(against.Z),
+		Direction:       face,
+		HeldItem:        b.Inventory.HeldItem(),
+		CursorPositionX: int8(cx * 16),
+		CursorPositionY: int8(cy * 16),
+		CursorPositionZ: int8(cz * 16),
+	})
+	b.Send(proto.ClientAnimation{EntityID: b.EntityID, Animation: swingAnimation})
+	pl.id, pl.sent = id, 0
+}
+
+// hotbarSlot returns the hotbar slot holding item, preferring
+// the one selected, or -1 if there is none.
+func (b *Bot) hotbarSlot(item int) int {
+	if s := b.Inventory.HeldItem(); int(s.Id) == item && s.Count > 0 {
+		return b.Inventory.Held
+	}
+	for i := 0; i < 9; i++ {
+		if s := b.Inventory.Hotbar(i); int(s.Id) == item && s.Count > 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// supportFace returns the block next to t to click for placing a block
+// at t, and the face of it towards t. The face within reach closest to
+// the eyes of b is chosen.
+func (b *Bot) supportFace(t pathfind.Pos) (against pathfind.Pos, face int8, ok bool) {
+	ex, ey, ez := b.eyes()
+	best := math.Inf(1)
+	for f, d := range faceDirs {
+		p := pathfind.Pos{X: t.X - d.X, Y: t.Y - d.Y, Z: t.Z - d.Z}
+		id, meta := b.World.Block(p.X, p.Y, p.Z)
+		if blocks.Passable(id, meta) || blocks.ByID(id).Interactive {
+			continue
+		}
+		x, y, z := float64(t.X)+0.5-float64(d.X)/2, float64(t.Y)+0.5-float64(d.Y)/2, float64(t.Z)+0.5-float64(d.Z)/2
+		dist := math.Sqrt((x-ex)*(x-ex) + (y-ey)*(y-ey) + (z-ez)*(z-ez))
+		if dist <= Reach && dist < best {
+			against, face, ok, best = p, int8(f), true, dist
+		}
+	}
+	return
+}
+
+// occupies reports if the bounding box of b intersects block p.
+func (b *Bot) occupies(p pathfind.Pos) bool {
+	x, y, z := float64(p.X), float64(p.Y), float64(p.Z)
+	block := physics.Box(x, y, z, x+1, y+1, z+1)
+	pb := physics.Box(b.X-physics.Width/2, b.Y, b.Z-physics.Width/2,
+		b.X+physics.Width/2, b.Y+physics.Height, b.Z+physics.Width/2)
+	return block.Intersects(pb)
+}
+
+// replaceable reports if a block may be placed in place of block id.
+func replaceable(id, meta int) bool {
+	return blocks.ByID(id).Replaceable || (id == blocks.SnowLayer && meta&7 == 0)
+}
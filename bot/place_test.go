@@ -0,0 +1,80 @@
+package bot
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/tajtiattila/mctoy/pathfind"
+	proto "github.com/tajtiattila/mctoy/protocol"
+)
+
+func TestPlaceBlock(t *testing.T) {
+	b, sent := newTestBot(t)
+	cobble := proto.Slot{Id: 4, Count: 10}
+	b.Inventory.Slots[SlotHotbar+2] = cobble
+	var result error
+	done := false
+	b.PlaceBlock(pathfind.Pos{X: 2, Y: 64, Z: 0}, 4, func(err error) { result, done = err, true })
+	b.Sched.Tick()
+	b.Sched.Tick()
+	if done {
+		t.Fatal("done before block change")
+	}
+	b.updateWorld(nil, &proto.BlockChange{X: 2, Y: 64, Z: 0, BlockType: 4})
+	b.Sched.Tick()
+	if !done || result != nil {
+		t.Fatalf("done %v, err %v", done, result)
+	}
+	if b.Pitch <= 0 {
+		t.Errorf("not looking down at the floor, pitch %v", b.Pitch)
+	}
+	want := []interface{}{
+		proto.ClientHeldItemChange{Slot: 2},
+		proto.PlayerBlockPlacement{
+			X: 2, Y: 63, Z: 0, Direction: FaceTop, HeldItem: cobble,
+			CursorPositionX: 8, CursorPositionY: 16, CursorPositionZ: 8,
+		},
+		proto.ClientAnimation{EntityID: 42, Animation: 1},
+	}
+	if got := sent.packets(b); !reflect.DeepEqual(got, want) {
+		t.Errorf("sent %v\nwant %v", got, want)
+	}
+}
+
+func TestPlaceBlockErrors(t *testing.T) {
+	b, _ := newTestBot(t)
+	b.Inventory.Slots[SlotHotbar] = proto.Slot{Id: 4, Count: 1}
+	b.Inventory.Slots[SlotHotbar+1] = proto.Slot{Id: 50, Count: 1}
+	b.Inventory.Slots[SlotHotbar+2] = proto.Slot{Id: 323, Count: 1}
+	b.Inventory.Slots[SlotHotbar+3] = proto.Slot{Id: 324, Count: 1}
+	b.World.SetBlock(1, 64, 2, 54, 0)
+	tests := []struct {
+		pos  pathfind.Pos
+		item int
+		err  error
+	}{
+		{pathfind.Pos{X: 1, Y: 64, Z: 0}, 5, ErrNoItem},
+		{pathfind.Pos{X: 1, Y: 63, Z: 0}, 4, ErrOccupied},
+		{pathfind.Pos{X: 0, Y: 64, Z: 0}, 4, ErrObstructed},
+		{pathfind.Pos{X: 1, Y: 67, Z: 0}, 4, ErrNoSupport},
+		{pathfind.Pos{X: 9, Y: 64, Z: 9}, 4, ErrNoSupport},
+		{pathfind.Pos{X: 0, Y: 64, Z: 0}, 50, ErrPlaceRejected},
+		{pathfind.Pos{X: 0, Y: 64, Z: 0}, 323, ErrPlaceRejected}, // sign
+		{pathfind.Pos{X: 0, Y: 64, Z: 0}, 324, ErrObstructed},    // door
+	}
+	for _, tt := range tests {
+		var err error
+		b.PlaceBlock(tt.pos, tt.item, func(e error) { err = e })
+		for i := 0; i < placeConfirmTicks+2 && b.place != nil; i++ {
+			b.Sched.Tick()
+		}
+		if err != tt.err {
+			t.Errorf("%v item %d: got %v, want %v", tt.pos, tt.item, err, tt.err)
+		}
+	}
+
+	// the chest is closer, but the floor is clicked
+	if p, face, ok := b.supportFace(pathfind.Pos{X: 1, Y: 64, Z: 3}); !ok || face != FaceTop || p.Y != 63 {
+		t.Errorf("support %v face %v %v", p, face, ok)
+	}
+}
@@ -0,0 +1,230 @@
+// Package bot implements game clients acting on their own.
+package bot
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// TickDuration is the length of a game tick.
+const TickDuration = time.Second / 20
+
+// Clock drives a Scheduler.
+type Clock interface {
+	// Start calls tick every d until stop is called. Calls to tick
+	// must not overlap.
+	Start(d time.Duration, tick func()) (stop func())
+}
+
+// RealClock is a Clock using the system time.
+var RealClock Clock = realClock{}
+
+type realClock struct{}
+
+func (realClock) Start(d time.Duration, tick func()) func() {
+	t := time.NewTicker(d)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-t.C:
+				tick()
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		t.Stop()
+		close(done)
+	}
+}
+
+// FakeClock is a Clock for tests, ticking only when Advance is called.
+type FakeClock struct {
+	mtx   sync.Mutex
+	ticks []func()
+}
+
+func (f *FakeClock) Start(d time.Duration, tick func()) func() {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	f.ticks = append(f.ticks, tick)
+	i := len(f.ticks) - 1
+	return func() {
+		f.mtx.Lock()
+		f.ticks[i] = nil
+		f.mtx.Unlock()
+	}
+}
+
+// Advance ticks the started schedulers n times,
+// and returns when the ticks are processed.
+func (f *FakeClock) Advance(n int) {
+	for ; n > 0; n-- {
+		f.mtx.Lock()
+		v := append([]func(){}, f.ticks...)
+		f.mtx.Unlock()
+		for _, tick := range v {
+			if tick != nil {
+				tick()
+			}
+		}
+	}
+}
+
+// Scheduler runs tasks at game ticks. Tasks run with the state lock of
+// the scheduler held, which should also be held when accessing the state
+// from other goroutines, eg. from packet handlers.
+type Scheduler struct {
+	// Clock is the clock driving Run, RealClock if nil.
+	Clock Clock
+
+	mtx sync.Mutex // state lock
+
+	tmtx  sync.Mutex // guards fields below
+	tick  int64
+	seq   int64
+	tasks taskHeap
+}
+
+// Task is a function scheduled in a Scheduler.
+type Task struct {
+	f      func()
+	due    int64
+	period int64 // zero for one-shot tasks
+	seq    int64
+	index  int // in heap, -1 if not scheduled
+}
+
+// NewScheduler returns a Scheduler without tasks.
+func NewScheduler() *Scheduler {
+	return new(Scheduler)
+}
+
+// Lock acquires the state lock.
+func (s *Scheduler) Lock() { s.mtx.Lock() }
+
+// Unlock releases the state lock.
+func (s *Scheduler) Unlock() { s.mtx.Unlock() }
+
+// Do calls f with the state lock held.
+func (s *Scheduler) Do(f func()) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	f()
+}
+
+// Ticks returns the number of ticks elapsed.
+func (s *Scheduler) Ticks() int64 {
+	s.tmtx.Lock()
+	defer s.tmtx.Unlock()
+	return s.tick
+}
+
+// After schedules f to run once, n ticks from now.
+// Tasks scheduled for the same tick run in the order scheduled.
+func (s *Scheduler) After(n int, f func()) *Task {
+	return s.schedule(n, 0, f)
+}
+
+// Every schedules f to run every n ticks, starting with the next tick.
+func (s *Scheduler) Every(n int, f func()) *Task {
+	if n < 1 {
+		n = 1
+	}
+	return s.schedule(1, n, f)
+}
+
+func (s *Scheduler) schedule(n, period int, f func()) *Task {
+	if n < 1 {
+		n = 1
+	}
+	s.tmtx.Lock()
+	defer s.tmtx.Unlock()
+	s.seq++
+	t := &Task{f: f, due: s.tick + int64(n), period: int64(period), seq: s.seq}
+	heap.Push(&s.tasks, t)
+	return t
+}
+
+// Cancel removes t from the scheduler s. It may be called from tasks.
+func (s *Scheduler) Cancel(t *Task) {
+	s.tmtx.Lock()
+	defer s.tmtx.Unlock()
+	if t.index >= 0 {
+		heap.Remove(&s.tasks, t.index)
+	}
+	t.period = 0
+}
+
+// Tick advances the scheduler by one tick, and runs the tasks due.
+func (s *Scheduler) Tick() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	s.tmtx.Lock()
+	s.tick++
+	now := s.tick
+	s.tmtx.Unlock()
+	for {
+		s.tmtx.Lock()
+		if len(s.tasks) == 0 || s.tasks[0].due > now {
+			s.tmtx.Unlock()
+			return
+		}
+		t := heap.Pop(&s.tasks).(*Task)
+		if t.period != 0 {
+			t.due += t.period
+			heap.Push(&s.tasks, t)
+		}
+		s.tmtx.Unlock()
+		t.f()
+	}
+}
+
+// Run ticks the scheduler using its Clock until ctx is done,
+// and returns ctx.Err().
+func (s *Scheduler) Run(ctx context.Context) error {
+	clock := s.Clock
+	if clock == nil {
+		clock = RealClock
+	}
+	stop := clock.Start(TickDuration, s.Tick)
+	<-ctx.Done()
+	stop()
+	return ctx.Err()
+}
+
+// taskHeap orders tasks by due tick, then by the order they were scheduled.
+type taskHeap []*Task
+
+func (h taskHeap) Len() int { return len(h) }
+
+func (h taskHeap) Less(i, j int) bool {
+	if h[i].due != h[j].due {
+		return h[i].due < h[j].due
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h taskHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+
+func (h *taskHeap) Push(x interface{}) {
+	t := x.(*Task)
+	t.index = len(*h)
+	*h = append(*h, t)
+}
+
+func (h *taskHeap) Pop() interface{} {
+	old := *h
+	t := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	t.index = -1
+	return t
+}
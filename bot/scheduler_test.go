@@ -0,0 +1,72 @@
+package bot
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestScheduler(t *testing.T) {
+	clock := new(FakeClock)
+	s := NewScheduler()
+	s.Clock = clock
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Run(ctx) }()
+	for {
+		clock.mtx.Lock()
+		n := len(clock.ticks)
+		clock.mtx.Unlock()
+		if n != 0 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	var got []string
+	log := func(s string) func() {
+		return func() { got = append(got, s) }
+	}
+	s.Every(1, log("tick"))
+	s.After(2, log("after2"))
+	every3 := s.Every(3, log("every3"))
+	s.After(2, func() {
+		got = append(got, "cancel")
+		s.Cancel(every3)
+		s.After(1, log("nested"))
+	})
+
+	clock.Advance(4)
+	want := []string{
+		"tick", "every3",
+		"tick", "after2", "cancel",
+		"tick", "nested",
+		"tick",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if n := s.Ticks(); n != 4 {
+		t.Errorf("got %d ticks, want 4", n)
+	}
+
+	got = nil
+	s.Do(func() {
+		s.After(2, log("again"))
+	})
+	clock.Advance(3)
+	want = []string{"tick", "tick", "again", "tick"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("got %v", err)
+	}
+	clock.Advance(1)
+	if s.Ticks() != 7 {
+		t.Errorf("ticked after stopped")
+	}
+}
@@ -0,0 +1,76 @@
+package bot
+
+import "github.com/tajtiattila/mctoy/blocks"
+
+// toolItem describes a tool.
+type toolItem struct {
+	tool  blocks.Tool
+	speed float64 // digging speed on blocks of its class
+	level int     // harvest level
+}
+
+// toolItems is indexed by item id.
+var toolItems = map[int]toolItem{
+	// wood
+	268: {blocks.ToolSword, 1.5, 0},
+	269: {blocks.ToolShovel, 2, 0},
+	270: {blocks.ToolPickaxe, 2, 0},
+	271: {blocks.ToolAxe, 2, 0},
+	// stone
+	272: {blocks.ToolSword, 1.5, 1},
+	273: {blocks.ToolShovel, 4, 1},
+	274: {blocks.ToolPickaxe, 4, 1},
+	275: {blocks.ToolAxe, 4, 1},
+	// iron
+	256: {blocks.ToolShovel, 6, 2},
+	257: {blocks.ToolPickaxe, 6, 2},
+	258: {blocks.ToolAxe, 6, 2},
+	267: {blocks.ToolSword, 1.5, 2},
+	// diamond
+	276: {blocks.ToolSword, 1.5, 3},
+	277: {blocks.ToolShovel, 8, 3},
+	278: {blocks.ToolPickaxe, 8, 3},
+	279: {blocks.ToolAxe, 8, 3},
+	// gold
+	283: {blocks.ToolSword, 1.5, 0},
+	284: {blocks.ToolShovel, 12, 0},
+	285: {blocks.ToolPickaxe, 12, 0},
+	286: {blocks.ToolAxe, 12, 0},
+
+	359: {blocks.ToolShears, 1, 0},
+}
+
+// itemBlocks maps item ids of items placing blocks to the block placed.
+var itemBlocks = map[int]int{
+	295: blocks.Wheat,
+	323: blocks.StandingSign,
+	324: blocks.WoodenDoor,
+	326: blocks.Water,
+	327: blocks.Lava,
+	330: blocks.IronDoor,
+	331: blocks.RedstoneWire,
+	338: blocks.Reeds,
+	354: blocks.Cake,
+	355: blocks.Bed,
+	356: blocks.UnpoweredRepeater,
+	361: blocks.PumpkinStem,
+	362: blocks.MelonStem,
+	372: blocks.NetherWart,
+	379: blocks.BrewingStand,
+	380: blocks.Cauldron,
+	390: blocks.FlowerPot,
+	391: blocks.Carrots,
+	392: blocks.Potatoes,
+	397: blocks.Skull,
+	404: blocks.UnpoweredComparator,
+}
+
+// placedBlock returns the block placed with item, or false
+// if it is not known.
+func placedBlock(item int) (id int, ok bool) {
+	if item < 256 {
+		return item, true
+	}
+	id, ok = itemBlocks[item]
+	return
+}
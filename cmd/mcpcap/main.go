@@ -0,0 +1,188 @@
+// Command mcpcap decodes Minecraft connections in libpcap capture files.
+//
+// Usage:
+//
+//	mcpcap [flags] file.pcap
+//
+// Every packet decoded is printed with the connection it belongs to.
+// Encrypted connections are decoded if their shared secret is given with
+// -secret, either as a hex string used for all connections, or as
+// client=hex to use it only for connections from client, eg.
+// 10.0.0.2:50000=000102030405060708090a0b0c0d0e0f.
+//
+// With -json, packets are printed as JSON objects, one per line.
+//
+// With -o, the packets of each connection are also written to capture
+// files that can be replayed with mctoy -replay.
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	mcnet "github.com/tajtiattila/mctoy/net"
+	"github.com/tajtiattila/mctoy/pcap"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+)
+
+var (
+	port   = flag.Int("port", 25565, "Minecraft server port")
+	output = flag.String("o", "", "write connections to capture files named `prefix`-N.cap")
+	quiet  = flag.Bool("q", false, "don't print packets")
+	jsonf  = flag.Bool("json", false, "print packets as JSON")
+	secret = make(secrets)
+)
+
+func main() {
+	flag.Var(secret, "secret", "AES shared secret as `[client=]hex`, may be repeated")
+	flag.Usage = func() {
+		fmt.Fprintln(os.Stderr, "usage: mcpcap [flags] file.pcap")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	f, err := os.Open(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	d, err := pcap.NewDecoder(f)
+	if err != nil {
+		log.Fatal(err)
+	}
+	d.Port = *port
+	d.Secret = secret.get
+
+	cws := make(map[int]*mcnet.CaptureWriter)
+	defer func() {
+		for _, cw := range cws {
+			cw.Close()
+		}
+	}()
+	for {
+		r, err := d.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		switch {
+		case *quiet:
+		case *jsonf:
+			printJSON(r)
+		default:
+			printRecord(r)
+		}
+		if *output == "" || r.Payload == nil {
+			continue
+		}
+		cw := cws[r.Stream.ID]
+		if cw == nil {
+			fn := fmt.Sprintf("%s-%d.cap", *output, r.Stream.ID)
+			if cw, err = mcnet.CreateCapture(fn); err != nil {
+				log.Fatal(err)
+			}
+			cws[r.Stream.ID] = cw
+		}
+		if err = cw.Write(&r.CaptureRecord); err != nil {
+			log.Fatal(err)
+		}
+	}
+}
+
+func printRecord(r *pcap.Record) {
+	ts := r.Time.Format("15:04:05.000")
+	s := r.Stream
+	if r.Err != nil {
+		fmt.Printf("%s #%d %s %s [%d bytes] %s->%s: %v\n", ts, s.ID, r.Dir,
+			proto.CxnStateString(r.State), len(r.Payload), s.Client, s.Server, r.Err)
+		return
+	}
+	fmt.Printf("%s #%d %s %s %s\n", ts, s.ID, r.Dir,
+		proto.CxnStateString(r.State), proto.PacketString(r.Packet))
+}
+
+type recordJSON struct {
+	Time   time.Time       `json:"time"`
+	Conn   int             `json:"conn"`
+	Client string          `json:"client"`
+	Server string          `json:"server"`
+	Dir    string          `json:"dir"`
+	State  string          `json:"state"`
+	Packet json.RawMessage `json:"packet,omitempty"`
+	Raw    []byte          `json:"raw,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+func printJSON(r *pcap.Record) {
+	rj := recordJSON{
+		Time:   r.Time,
+		Conn:   r.Stream.ID,
+		Client: r.Stream.Client.String(),
+		Server: r.Stream.Server.String(),
+		Dir:    "serverbound",
+		State:  proto.CxnStateString(r.State),
+	}
+	if r.Dir == mcnet.Clientbound {
+		rj.Dir = "clientbound"
+	}
+	err := r.Err
+	if err == nil {
+		hs := proto.GetHostState(1-r.Dir.Receiver(), r.State)
+		rj.Packet, err = hs.EncodeJSON(r.Packet)
+	}
+	if err != nil {
+		rj.Raw, rj.Error = r.Payload, err.Error()
+	}
+	b, err := json.Marshal(rj)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%s\n", b)
+}
+
+// secrets maps client addresses to shared secrets,
+// the empty key holds the secret for any client.
+type secrets map[string][]byte
+
+func (s secrets) String() string {
+	var v []string
+	for k, b := range s {
+		if k != "" {
+			k += "="
+		}
+		v = append(v, k+hex.EncodeToString(b))
+	}
+	return strings.Join(v, ",")
+}
+
+func (s secrets) Set(v string) error {
+	var client string
+	if i := strings.LastIndex(v, "="); i >= 0 {
+		client, v = v[:i], v[i+1:]
+	}
+	b, err := hex.DecodeString(v)
+	if err != nil {
+		return err
+	}
+	s[client] = b
+	return nil
+}
+
+func (s secrets) get(client, server pcap.Endpoint) []byte {
+	if b, ok := s[client.String()]; ok {
+		return b
+	}
+	return s[""]
+}
@@ -0,0 +1,169 @@
+// Command mcproxy is a debugging proxy between a game client and a server.
+// It relays traffic in both directions and logs every packet decoded.
+//
+// In offline mode packets are relayed transparently, and the server must
+// be in offline mode as well. In online mode the proxy performs its own key
+// exchange with the client, and logs in to the server with the account
+// configured for the proxy, so traffic is re-encrypted on each side.
+package main
+
+import (
+	crand "crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"flag"
+	"fmt"
+	mcnet "github.com/tajtiattila/mctoy/net"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"github.com/tajtiattila/passwdprompt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	listen   = flag.String("listen", ":25566", "address to listen on for game clients")
+	server   = flag.String("addr", "localhost:25565", "Minecraft server address")
+	online   = flag.Bool("online", false, "re-encrypt traffic, log in to the server with own account")
+	authfile = flag.String("auth", ".mcproxy-auth", "file to keep access tokens in, online mode only")
+	rulefile = flag.String("rules", "", "packet rewrite rule file, reloaded when changed")
+)
+
+func main() {
+	flag.Parse()
+
+	var p proxy
+	if *online {
+		var err error
+		if p.key, err = rsa.GenerateKey(crand.Reader, 1024); err != nil {
+			log.Fatal(err)
+		}
+		ya := mcnet.NewYggAuth(jsonStore(*authfile),
+			mcnet.UserPassworderFunc(func() (u, p string, err error) {
+				return passwdprompt.GetUserPassword("Username: ", "Password: ")
+			}))
+		ya.Logger = mcnet.NewTextLogger(os.Stderr, mcnet.LevelInfo)
+		p.auth = ya
+	}
+
+	if *rulefile != "" {
+		rf, err := mcnet.LoadRuleFile(*rulefile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		rf.OnError = func(err error) { log.Println("Rules not reloaded:", err) }
+		p.filter = rf
+	}
+
+	l, err := net.Listen("tcp", *listen)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Println("Listening on", l.Addr(), "relaying to", *server)
+	for id := 1; ; id++ {
+		c, err := mcnet.Accept(l)
+		if err != nil {
+			log.Println("Accept:", err)
+			time.Sleep(100 * time.Millisecond)
+			continue
+		}
+		go func(id int) {
+			err := p.handle(c, &prefixWriter{w: os.Stdout, pfx: fmt.Sprintf("#%d ", id)})
+			log.Printf("#%d closed: %v", id, err)
+		}(id)
+	}
+}
+
+type proxy struct {
+	key  *rsa.PrivateKey // nil in offline mode
+	amtx sync.Mutex      // serializes logins with auth
+	auth mcnet.Auth
+
+	filter mcnet.PacketFilter
+}
+
+func (p *proxy) handle(c *mcnet.ServerConn, logw io.Writer) error {
+	defer c.Close()
+	r := &mcnet.Relay{Client: &c.Conn, Filter: p.filter, Log: logw}
+	if p.key == nil {
+		sc, err := mcnet.Connect(*server)
+		if err != nil {
+			return err
+		}
+		defer sc.Close()
+		r.Server = &sc.Conn
+		return r.Run()
+	}
+
+	h, err := c.ReadHandshake()
+	if err != nil {
+		return err
+	}
+	sc, err := mcnet.Connect(*server)
+	if err != nil {
+		return err
+	}
+	defer sc.Close()
+	r.Server = &sc.Conn
+
+	if h.StateUpdate() == proto.StateStatus {
+		if err = sc.Handshake(proto.StateStatus); err != nil {
+			return err
+		}
+		return r.Run()
+	}
+
+	name, err := c.AcceptLogin(p.key)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(logw, "client logging in as %s\n", name)
+	p.amtx.Lock()
+	err = sc.Login(p.auth)
+	p.amtx.Unlock()
+	if err != nil {
+		c.LoginDisconnect(fmt.Sprintf(`{"text":"mcproxy: %s"}`, err))
+		return err
+	}
+	ls := sc.LoginSuccess
+	if err = c.LoginSuccess(ls.UUID, ls.Username); err != nil {
+		return err
+	}
+	return r.Run()
+}
+
+// jsonStore is a PersistentStore keeping values in a JSON file.
+type jsonStore string
+
+func (s jsonStore) Load(v interface{}) error {
+	b, err := ioutil.ReadFile(string(s))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func (s jsonStore) Save(v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(string(s), b, 0600)
+}
+
+// prefixWriter writes pfx before every Write into w.
+type prefixWriter struct {
+	w   io.Writer
+	pfx string
+}
+
+func (p *prefixWriter) Write(b []byte) (int, error) {
+	if _, err := io.WriteString(p.w, p.pfx+string(b)); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
@@ -0,0 +1,176 @@
+// Command nbt inspects and converts NBT files such as level.dat
+// or player data.
+//
+// Usage:
+//
+//	nbt [print] file              pretty-print file
+//	nbt json file                 convert file to JSON
+//	nbt unjson [-c comp] in out   convert JSON file in to NBT file out
+//	nbt get file path             print the value at path, eg. Data.SpawnX
+//	nbt diff file1 file2          print structural differences
+//
+// Compression of input files is detected automatically, "-" reads
+// standard input.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/tajtiattila/mctoy/nbt"
+	"io/ioutil"
+	"os"
+)
+
+var (
+	compression = flag.String("c", "gzip", "compression for unjson output: gzip, zlib or none")
+	indent      = flag.Bool("indent", true, "indent JSON output")
+)
+
+var errUsage = errors.New("usage: nbt [print|json|unjson|get|diff] args...")
+
+func main() {
+	flag.Parse()
+	args := flag.Args()
+	if len(args) == 1 {
+		args = []string{"print", args[0]}
+	}
+	if len(args) < 2 {
+		fail(errUsage)
+	}
+	var err error
+	switch cmd, a := args[0], args[1:]; {
+	case cmd == "print" && len(a) == 1:
+		err = printCmd(a[0])
+	case cmd == "json" && len(a) == 1:
+		err = jsonCmd(a[0])
+	case cmd == "unjson":
+		fs := flag.NewFlagSet("unjson", flag.ExitOnError)
+		fs.StringVar(compression, "c", *compression, "compression of out: gzip, zlib or none")
+		fs.Parse(a)
+		if fs.NArg() != 2 {
+			err = errUsage
+			break
+		}
+		err = unjsonCmd(fs.Arg(0), fs.Arg(1))
+	case cmd == "get" && len(a) == 2:
+		err = getCmd(a[0], a[1])
+	case cmd == "diff" && len(a) == 2:
+		var same bool
+		if same, err = diffCmd(a[0], a[1]); err == nil && !same {
+			os.Exit(1)
+		}
+	default:
+		err = errUsage
+	}
+	if err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(2)
+}
+
+func readFile(fn string) ([]byte, error) {
+	if fn == "-" {
+		return ioutil.ReadAll(os.Stdin)
+	}
+	return ioutil.ReadFile(fn)
+}
+
+func readTag(fn string) (nbt.Tag, error) {
+	b, err := readFile(fn)
+	if err != nil {
+		return nbt.Tag{}, err
+	}
+	t, _, err := nbt.Read(b)
+	return t, err
+}
+
+func printCmd(fn string) error {
+	t, err := readTag(fn)
+	if err != nil {
+		return err
+	}
+	return nbt.Fprint(os.Stdout, t)
+}
+
+func jsonCmd(fn string) error {
+	t, err := readTag(fn)
+	if err != nil {
+		return err
+	}
+	var b []byte
+	if *indent {
+		b, err = json.MarshalIndent(t, "", "  ")
+	} else {
+		b, err = json.Marshal(t)
+	}
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(append(b, '\n'))
+	return err
+}
+
+func unjsonCmd(in, out string) error {
+	var c nbt.Compression
+	switch *compression {
+	case "gzip":
+		c = nbt.Gzip
+	case "zlib":
+		c = nbt.Zlib
+	case "none":
+		c = nbt.Uncompressed
+	default:
+		return errors.New("invalid compression: " + *compression)
+	}
+	b, err := readFile(in)
+	if err != nil {
+		return err
+	}
+	var t nbt.Tag
+	if err = json.Unmarshal(b, &t); err != nil {
+		return err
+	}
+	return nbt.WriteFile(out, t, c)
+}
+
+func getCmd(fn, path string) error {
+	t, err := readTag(fn)
+	if err != nil {
+		return err
+	}
+	v, err := nbt.Lookup(t.Value, path)
+	if err != nil {
+		return err
+	}
+	switch v.(type) {
+	case nbt.Compound, *nbt.List:
+		return nbt.Fprint(os.Stdout, nbt.Tag{Value: v})
+	}
+	_, err = fmt.Println(nbt.ValueString(v))
+	return err
+}
+
+func diffCmd(fn1, fn2 string) (same bool, err error) {
+	t1, err := readTag(fn1)
+	if err != nil {
+		return false, err
+	}
+	t2, err := readTag(fn2)
+	if err != nil {
+		return false, err
+	}
+	d := nbt.Diff(t1.Value, t2.Value)
+	if t1.Name != t2.Name {
+		fmt.Printf("~ root name: %q -> %q\n", t1.Name, t2.Name)
+	}
+	for _, x := range d {
+		fmt.Println(x)
+	}
+	return len(d) == 0 && t1.Name == t2.Name, nil
+}
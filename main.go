@@ -1,80 +1,50 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"github.com/tajtiattila/mctoy/bot"
 	mcnet "github.com/tajtiattila/mctoy/net"
 	proto "github.com/tajtiattila/mctoy/protocol"
+	"github.com/tajtiattila/mctoy/world"
 	"github.com/tajtiattila/passwdprompt"
-	"io"
+	"net/http"
 	"os"
-	"reflect"
-	"sync"
+	"os/signal"
 	"time"
 )
 
 var (
-	server = flag.String("addr", "", "Minecraft server address")
+	server   = flag.String("addr", "", "Minecraft server address")
+	capture  = flag.String("capture", "", "record packets into capture file")
+	replay   = flag.String("replay", "", "replay capture file instead of connecting")
+	flight   = flag.Int("flight", 1000, "number of recent packets written to a file on errors, 0 to disable")
+	loglvl   = flag.String("log", "", "log messages at or above `level` (debug, info, warn or error) to stderr")
+	watchdog = flag.Duration("watchdog", 30*time.Second, "disconnect if the server is silent for this long, 0 to disable")
+	metrics  = flag.String("metrics", "", "serve connection metrics over HTTP on `addr` at /metrics and /debug/vars")
+	packets  = new(mcnet.PacketTypeFilter)
 )
 
-type DemoHandler struct {
-	mtx        sync.Mutex
-	responder  bool
-	PlayerID   int32
-	X, Y, Z    float64
-	Yaw, Pitch float32
-	OnGround   bool
-	log        io.ReadWriter
-}
+func main() {
+	flag.Var(packets, "packets", "packet types to log at debug level, eg. *,-KeepAlive")
+	flag.Parse()
 
-func (h *DemoHandler) SendPosition(c *mcnet.Conn) error {
-	h.mtx.Lock()
-	defer h.mtx.Unlock()
-	if h.PlayerID == 0 {
-		// not joined yet
-		return nil
+	var logger mcnet.Logger
+	if *loglvl != "" {
+		l, err := mcnet.ParseLevel(*loglvl)
+		if err != nil {
+			fail(err)
+		}
+		logger = mcnet.NewTextLogger(os.Stderr, l)
 	}
-	return c.Send(proto.ClientPlayerPositionAndLook{
-		X:        h.X,
-		Y:        h.Y,
-		Z:        h.Z,
-		Stance:   2.0,
-		Yaw:      h.Yaw,
-		Pitch:    h.Pitch,
-		OnGround: h.OnGround,
-	})
-}
 
-func (h *DemoHandler) HandlePacket(c *mcnet.Conn, pk interface{}) (err error) {
-	h.mtx.Lock()
-	defer h.mtx.Unlock()
-	fmt.Fprintln(h.log, reflect.TypeOf(pk))
-	switch p := pk.(type) {
-	case *proto.KeepAlive:
-		err = c.Send(p)
-		return
-	case *proto.JoinGame:
-		h.PlayerID = p.EntityID
-	case *proto.ServerPlayerPositionAndLook:
-		h.X, h.Y, h.Z = p.X, p.Y, p.Z
-		h.Yaw, h.Pitch = p.Yaw, p.Pitch
-		h.OnGround = p.OnGround
-	case *proto.MapChunkBulk:
-		if !h.responder {
-			h.responder = true
-			go func() {
-				for _ = range time.Tick(time.Second / 20) {
-					err := h.SendPosition(c)
-					fmt.Fprintln(h.log, "-> SendPosition:", err)
-				}
-			}()
+	if *replay != "" {
+		if err := runReplay(*replay); err != nil {
+			fail(err)
 		}
+		return
 	}
-	return
-}
-
-func main() {
-	flag.Parse()
 
 	cfg, err := NewUserConfig(".mcbot-config")
 	if err != nil {
@@ -96,29 +66,105 @@ func main() {
 	if err != nil {
 		fail(err)
 	}
+	c.SetLogger(logger)
+	c.SetPacketLog(packets)
+	c.SetAutoKeepAlive(true)
+	c.SetWatchdog(*watchdog)
+
+	if *metrics != "" {
+		mr := mcnet.NewMetricsRegistry()
+		mr.Add(&c.Conn)
+		mr.Publish("mctoy")
+		http.Handle("/metrics", mr)
+		go func() {
+			fail(http.ListenAndServe(*metrics, nil))
+		}()
+	}
+
+	if *capture != "" {
+		cw, err := mcnet.CreateCapture(*capture)
+		if err != nil {
+			fail(err)
+		}
+		defer cw.Close()
+		c.SetCapture(cw)
+	}
+
+	if *flight > 0 {
+		fr := mcnet.NewFlightRecorder(*flight)
+		fr.OnDump = func(fn string, err error) {
+			if err != nil {
+				fmt.Println("Flight recorder:", err)
+			} else {
+				fmt.Println("Recent packets written to", fn)
+			}
+		}
+		c.SetFlightRecorder(fr)
+	}
 
 	var a mcnet.Auth
-	a = mcnet.NewYggAuth(
+	ya := mcnet.NewYggAuth(
 		NewConfigStore("auth", cfg),
 		mcnet.UserPassworderFunc(func() (u, p string, err error) {
 			return passwdprompt.GetUserPassword("Username: ", "Password: ")
 		}),
 	)
+	ya.Logger = logger
+	a = ya
 	a = mcnet.NewNoAuth("Sándorvagyok")
 	err = c.Login(a)
 	if err != nil {
 		fail(err)
 	}
 
-	h := &DemoHandler{log: NewRoundBuf()}
-	err = c.Run(h)
-	//io.Copy(os.Stdout, h.log)
+	// disconnect on interrupt
+	ctx, cancel := context.WithCancel(context.Background())
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, os.Interrupt)
+	go func() {
+		<-sigc
+		cancel()
+	}()
 
-	if err != nil {
+	d := mcnet.NewDispatcher()
+	b := bot.New(&c.Conn)
+	b.Register(d)
+	go b.Run(ctx)
+
+	err = c.RunPipeline(ctx, d, &mcnet.Pipeline{Process: world.Process})
+	if err != nil && err != context.Canceled {
 		fail(err)
 	}
 }
 
+// runReplay runs a bot on the packets recorded in capture file fn.
+func runReplay(fn string) error {
+	cr, err := mcnet.OpenCapture(fn)
+	if err != nil {
+		return err
+	}
+	defer cr.Close()
+	r, err := mcnet.NewReplay(cr)
+	if err != nil {
+		return err
+	}
+	r.Log = os.Stdout
+	r.Conn().SetAutoKeepAlive(true)
+	r.Ignore = func(p interface{}) bool {
+		// sent on a timer
+		_, ok := p.(*proto.ClientPlayerPositionAndLook)
+		return ok
+	}
+	// position updates are ignored, the scheduler of the bot is not run
+	d := mcnet.NewDispatcher()
+	bot.New(r.Conn()).Register(d)
+	if err = r.Run(d); err != nil {
+		return err
+	}
+	fmt.Println("Replay finished,", len(r.Mismatches()), "mismatches")
+	return nil
+}
+
 func fail(err error) {
 	fmt.Println(err)
 	os.Exit(0)
@@ -0,0 +1,119 @@
+package nbt
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io/ioutil"
+)
+
+// Compression is the compression used for an NBT stream.
+type Compression int
+
+const (
+	Uncompressed Compression = iota
+	Gzip                     // level.dat, player data, Slot tags
+	Zlib                     // region file chunks
+)
+
+func (c Compression) String() string {
+	switch c {
+	case Uncompressed:
+		return "none"
+	case Gzip:
+		return "gzip"
+	case Zlib:
+		return "zlib"
+	}
+	return "invalid"
+}
+
+// DetectCompression guesses the compression of b from its first bytes.
+func DetectCompression(b []byte) Compression {
+	if len(b) >= 2 {
+		if b[0] == 0x1f && b[1] == 0x8b {
+			return Gzip
+		}
+		if b[0]&0x0f == 8 && (uint(b[0])<<8|uint(b[1]))%31 == 0 {
+			return Zlib
+		}
+	}
+	return Uncompressed
+}
+
+// Decompress decompresses b, auto-detecting the compression used.
+func Decompress(b []byte) ([]byte, Compression, error) {
+	c := DetectCompression(b)
+	var (
+		d   []byte
+		err error
+	)
+	switch c {
+	case Gzip:
+		var r *gzip.Reader
+		if r, err = gzip.NewReader(bytes.NewReader(b)); err == nil {
+			d, err = ioutil.ReadAll(r)
+		}
+	case Zlib:
+		r, e := zlib.NewReader(bytes.NewReader(b))
+		if err = e; err == nil {
+			d, err = ioutil.ReadAll(r)
+		}
+	default:
+		d = b
+	}
+	return d, c, err
+}
+
+// Compress compresses b using c.
+func Compress(b []byte, c Compression) ([]byte, error) {
+	var buf bytes.Buffer
+	switch c {
+	case Gzip:
+		w := gzip.NewWriter(&buf)
+		w.Write(b)
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+	case Zlib:
+		w := zlib.NewWriter(&buf)
+		w.Write(b)
+		if err := w.Close(); err != nil {
+			return nil, err
+		}
+	default:
+		return b, nil
+	}
+	return buf.Bytes(), nil
+}
+
+// ReadFile reads the possibly compressed NBT file fn.
+func ReadFile(fn string) (Tag, Compression, error) {
+	b, err := ioutil.ReadFile(fn)
+	if err != nil {
+		return Tag{}, Uncompressed, err
+	}
+	return Read(b)
+}
+
+// Read decodes the possibly compressed NBT data in b.
+func Read(b []byte) (Tag, Compression, error) {
+	d, c, err := Decompress(b)
+	if err != nil {
+		return Tag{}, c, err
+	}
+	t, err := DecodeTag(d)
+	return t, c, err
+}
+
+// WriteFile writes t into fn using compression c.
+func WriteFile(fn string, t Tag, c Compression) error {
+	b, err := EncodeTag(t)
+	if err != nil {
+		return err
+	}
+	if b, err = Compress(b, c); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fn, b, 0644)
+}
@@ -19,7 +19,7 @@ var (
 )
 
 func (c *decoder) Get(n int) []byte {
-	if c.p+n < len(c.buf) {
+	if c.p+n <= len(c.buf) {
 		s := c.p
 		c.p += n
 		return c.buf[s:c.p]
@@ -33,7 +33,7 @@ func (c *decoder) readNamed(v reflect.Value) {
 func (c *decoder) Kind() TagKind {
 	k := TagKind(c.Byte())
 	if TagIntArray < k {
-		panic(ErrKindUnknown{k})
+		panic(&ErrKindUnknown{k})
 	}
 	return k
 }
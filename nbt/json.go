@@ -0,0 +1,252 @@
+package nbt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+/*
+
+JSON representation of NBT data carries the tag kinds along with
+the values, so that converting it back to NBT is lossless.
+
+A named tag is an object:
+
+ {"name": "Pos", "type": "list", "value": ...}
+
+The value depends on the type:
+
+ Type               JSON value
+ ====               ==========
+ byte,short,int     number
+ long               number (exact, decode with json.Number)
+ float,double       number, or "NaN", "Infinity", "-Infinity"
+ bytearray          base64 string
+ string             string
+ intarray           array of numbers
+ list               {"elem": "double", "items": [values...]}
+ compound           array of named tags
+
+*/
+
+var kindNames = map[TagKind]string{TagEnd: "end"}
+
+func init() {
+	for n, k := range mapKind {
+		kindNames[k] = n
+	}
+}
+
+func kindName(k TagKind) string {
+	if n, ok := kindNames[k]; ok {
+		return n
+	}
+	return k.String()
+}
+
+type jsonTag struct {
+	Name  string          `json:"name"`
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+type jsonList struct {
+	Elem  string            `json:"elem"`
+	Items []json.RawMessage `json:"items"`
+}
+
+// MarshalJSON implements json.Marshaler.
+func (t Tag) MarshalJSON() ([]byte, error) {
+	if t.Value == nil {
+		return nil, ErrNoValue
+	}
+	v, err := marshalValue(t.Value)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(jsonTag{t.Name, kindName(t.Value.Kind()), v})
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *Tag) UnmarshalJSON(b []byte) error {
+	var jt jsonTag
+	if err := json.Unmarshal(b, &jt); err != nil {
+		return err
+	}
+	k, ok := mapKind[jt.Type]
+	if !ok {
+		return fmt.Errorf("NBT: invalid type %q for tag %q", jt.Type, jt.Name)
+	}
+	v, err := unmarshalValue(k, jt.Value)
+	if err != nil {
+		return fmt.Errorf("NBT: tag %q: %v", jt.Name, err)
+	}
+	t.Name, t.Value = jt.Name, v
+	return nil
+}
+
+func marshalFloat(f float64) interface{} {
+	switch {
+	case math.IsNaN(f):
+		return "NaN"
+	case math.IsInf(f, 1):
+		return "Infinity"
+	case math.IsInf(f, -1):
+		return "-Infinity"
+	}
+	return f
+}
+
+func marshalValue(v Value) (json.RawMessage, error) {
+	var i interface{}
+	switch x := v.(type) {
+	case Byte, Short, Int, Long, String, ByteArray:
+		i = x
+	case Float:
+		// format with float32 precision to keep the output short
+		if f := float64(x); !math.IsNaN(f) && !math.IsInf(f, 0) {
+			return json.RawMessage(strconv.FormatFloat(f, 'g', -1, 32)), nil
+		}
+		i = marshalFloat(float64(x))
+	case Double:
+		i = marshalFloat(float64(x))
+	case IntArray:
+		a := []int32(x)
+		if a == nil {
+			a = []int32{}
+		}
+		i = a
+	case *List:
+		l := jsonList{Elem: kindName(x.Elem), Items: []json.RawMessage{}}
+		for _, ev := range x.Values {
+			if ev == nil || ev.Kind() != x.Elem {
+				return nil, ErrListElemMixed
+			}
+			m, err := marshalValue(ev)
+			if err != nil {
+				return nil, err
+			}
+			l.Items = append(l.Items, m)
+		}
+		i = l
+	case Compound:
+		c := []Tag(x)
+		if c == nil {
+			c = []Tag{}
+		}
+		i = c
+	default:
+		return nil, errKindMismatch(TagInvalid, "while writing JSON")
+	}
+	return json.Marshal(i)
+}
+
+func unmarshalNumber(b []byte) (json.Number, error) {
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.UseNumber()
+	var n json.Number
+	err := d.Decode(&n)
+	return n, err
+}
+
+func unmarshalInt(b []byte, bits int) (int64, error) {
+	n, err := unmarshalNumber(b)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(string(n), 10, bits)
+}
+
+func unmarshalFloat(b []byte, bits int) (float64, error) {
+	var s string
+	if json.Unmarshal(b, &s) == nil {
+		switch s {
+		case "NaN":
+			return math.NaN(), nil
+		case "Infinity":
+			return math.Inf(1), nil
+		case "-Infinity":
+			return math.Inf(-1), nil
+		}
+		return 0, errors.New("invalid float: " + s)
+	}
+	n, err := unmarshalNumber(b)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(string(n), bits)
+}
+
+func unmarshalValue(k TagKind, b []byte) (v Value, err error) {
+	switch k {
+	case TagByte:
+		var i int64
+		i, err = unmarshalInt(b, 8)
+		v = Byte(i)
+	case TagShort:
+		var i int64
+		i, err = unmarshalInt(b, 16)
+		v = Short(i)
+	case TagInt:
+		var i int64
+		i, err = unmarshalInt(b, 32)
+		v = Int(i)
+	case TagLong:
+		var i int64
+		i, err = unmarshalInt(b, 64)
+		v = Long(i)
+	case TagFloat:
+		var f float64
+		f, err = unmarshalFloat(b, 32)
+		v = Float(f)
+	case TagDouble:
+		var f float64
+		f, err = unmarshalFloat(b, 64)
+		v = Double(f)
+	case TagByteArray:
+		var a []byte
+		err = json.Unmarshal(b, &a)
+		v = ByteArray(a)
+	case TagString:
+		var s string
+		err = json.Unmarshal(b, &s)
+		v = String(s)
+	case TagIntArray:
+		var a []int32
+		err = json.Unmarshal(b, &a)
+		v = IntArray(a)
+	case TagList:
+		var jl jsonList
+		if err = json.Unmarshal(b, &jl); err != nil {
+			return nil, err
+		}
+		ek, ok := mapKind[jl.Elem]
+		if !ok {
+			if jl.Elem != kindName(TagEnd) || len(jl.Items) != 0 {
+				return nil, fmt.Errorf("invalid list element type %q", jl.Elem)
+			}
+			ek = TagEnd
+		}
+		l := &List{Elem: ek, Values: make([]Value, len(jl.Items))}
+		for i, m := range jl.Items {
+			if l.Values[i], err = unmarshalValue(ek, m); err != nil {
+				return nil, fmt.Errorf("[%d]: %v", i, err)
+			}
+		}
+		v = l
+	case TagCompound:
+		c := Compound{}
+		err = json.Unmarshal(b, (*[]Tag)(&c))
+		v = c
+	default:
+		err = errKindMismatch(k, "while reading JSON")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
@@ -0,0 +1,89 @@
+package nbt
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maximum number of array elements printed
+const printArrayMax = 16
+
+// ValueString returns a short, single line description of v.
+func ValueString(v Value) string {
+	switch x := v.(type) {
+	case Byte, Short, Int, Long:
+		return fmt.Sprint(x)
+	case Float:
+		return strconv.FormatFloat(float64(x), 'g', -1, 32)
+	case Double:
+		return strconv.FormatFloat(float64(x), 'g', -1, 64)
+	case String:
+		return strconv.Quote(string(x))
+	case ByteArray:
+		return arrayString(len(x), func(i int) string { return fmt.Sprint(x[i]) })
+	case IntArray:
+		return arrayString(len(x), func(i int) string { return fmt.Sprint(x[i]) })
+	case *List:
+		return fmt.Sprintf("%d entries of %s", len(x.Values), kindName(x.Elem))
+	case Compound:
+		return fmt.Sprintf("%d entries", len(x))
+	}
+	return "<invalid>"
+}
+
+func arrayString(n int, f func(i int) string) string {
+	var s []string
+	for i := 0; i < n && i < printArrayMax; i++ {
+		s = append(s, f(i))
+	}
+	if n > printArrayMax {
+		s = append(s, fmt.Sprintf("... %d more", n-printArrayMax))
+	}
+	return fmt.Sprintf("[%d] {%s}", n, strings.Join(s, ", "))
+}
+
+// Fprint pretty-prints t into w.
+func Fprint(w io.Writer, t Tag) error {
+	p := printer{w: w}
+	p.tag(0, t.Name, true, t.Value)
+	return p.err
+}
+
+type printer struct {
+	w   io.Writer
+	err error
+}
+
+func (p *printer) printf(indent int, format string, a ...interface{}) {
+	if p.err == nil {
+		_, p.err = fmt.Fprintf(p.w, strings.Repeat("  ", indent)+format, a...)
+	}
+}
+
+func (p *printer) tag(indent int, name string, named bool, v Value) {
+	n := ""
+	if named {
+		n = "(" + strconv.Quote(name) + ")"
+	}
+	p.printf(indent, "%s%s: %s\n", v.Kind(), n, ValueString(v))
+	switch x := v.(type) {
+	case *List:
+		if len(x.Values) != 0 {
+			p.printf(indent, "{\n")
+			for _, ev := range x.Values {
+				p.tag(indent+1, "", false, ev)
+			}
+			p.printf(indent, "}\n")
+		}
+	case Compound:
+		if len(x) != 0 {
+			p.printf(indent, "{\n")
+			for _, t := range x {
+				p.tag(indent+1, t.Name, true, t.Value)
+			}
+			p.printf(indent, "}\n")
+		}
+	}
+}
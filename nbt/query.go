@@ -0,0 +1,170 @@
+package nbt
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Lookup returns the value found at path within v.
+// Path elements are compound tag names separated by dots,
+// list and array elements are selected with an index in brackets:
+//
+//	Data.Player.Inventory[0].id
+//
+// An empty path returns v itself.
+func Lookup(v Value, path string) (Value, error) {
+	p := path
+	for p != "" {
+		var elem string
+		switch i := strings.IndexAny(p[1:], ".["); {
+		case p[0] == '[':
+			e := strings.IndexRune(p, ']')
+			if e < 0 {
+				return nil, fmt.Errorf("NBT: unterminated index in path %q", path)
+			}
+			elem, p = p[:e+1], p[e+1:]
+		case i < 0:
+			elem, p = p, ""
+		default:
+			elem, p = p[:i+1], p[i+1:]
+		}
+		if elem[0] == '[' {
+			n, err := strconv.Atoi(elem[1 : len(elem)-1])
+			if err != nil {
+				return nil, fmt.Errorf("NBT: invalid index %s in path %q", elem, path)
+			}
+			if v = index(v, n); v == nil {
+				return nil, fmt.Errorf("NBT: no element %s in path %q", elem, path)
+			}
+			continue
+		}
+		elem = strings.TrimPrefix(elem, ".")
+		c, ok := v.(Compound)
+		if !ok {
+			return nil, fmt.Errorf("NBT: %s is not a compound in path %q", kindName(v.Kind()), path)
+		}
+		if v = c.Get(elem); v == nil {
+			return nil, fmt.Errorf("NBT: no tag %q in path %q", elem, path)
+		}
+	}
+	return v, nil
+}
+
+func index(v Value, n int) Value {
+	switch x := v.(type) {
+	case *List:
+		if 0 <= n && n < len(x.Values) {
+			return x.Values[n]
+		}
+	case ByteArray:
+		if 0 <= n && n < len(x) {
+			return Byte(x[n])
+		}
+	case IntArray:
+		if 0 <= n && n < len(x) {
+			return Int(x[n])
+		}
+	}
+	return nil
+}
+
+// Difference is a structural difference between two values.
+// Old is nil for added, New is nil for removed values.
+type Difference struct {
+	Path     string
+	Old, New Value
+}
+
+func (d Difference) String() string {
+	switch {
+	case d.Old == nil:
+		return fmt.Sprintf("+ %s: %s", d.Path, ValueString(d.New))
+	case d.New == nil:
+		return fmt.Sprintf("- %s: %s", d.Path, ValueString(d.Old))
+	}
+	return fmt.Sprintf("~ %s: %s -> %s", d.Path, ValueString(d.Old), ValueString(d.New))
+}
+
+// Diff returns the differences between a and b.
+// Compounds are compared tag by tag regardless of tag order,
+// lists element by element.
+func Diff(a, b Value) []Difference {
+	return diff(nil, "", a, b)
+}
+
+func diff(d []Difference, path string, a, b Value) []Difference {
+	if a.Kind() != b.Kind() {
+		return append(d, Difference{path, a, b})
+	}
+	switch x := a.(type) {
+	case Compound:
+		y := b.(Compound)
+		for _, t := range x {
+			p := joinPath(path, t.Name)
+			if bv := y.Get(t.Name); bv != nil {
+				d = diff(d, p, t.Value, bv)
+			} else {
+				d = append(d, Difference{p, t.Value, nil})
+			}
+		}
+		for _, t := range y {
+			if x.Get(t.Name) == nil {
+				d = append(d, Difference{joinPath(path, t.Name), nil, t.Value})
+			}
+		}
+	case *List:
+		y := b.(*List)
+		if x.Elem != y.Elem && len(x.Values) != 0 && len(y.Values) != 0 {
+			return append(d, Difference{path, a, b})
+		}
+		for i := 0; i < len(x.Values) || i < len(y.Values); i++ {
+			p := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(y.Values):
+				d = append(d, Difference{p, x.Values[i], nil})
+			case i >= len(x.Values):
+				d = append(d, Difference{p, nil, y.Values[i]})
+			default:
+				d = diff(d, p, x.Values[i], y.Values[i])
+			}
+		}
+	default:
+		if !equal(a, b) {
+			d = append(d, Difference{path, a, b})
+		}
+	}
+	return d
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func equal(a, b Value) bool {
+	switch x := a.(type) {
+	case ByteArray:
+		return bytes.Equal(x, b.(ByteArray))
+	case IntArray:
+		y := b.(IntArray)
+		if len(x) != len(y) {
+			return false
+		}
+		for i := range x {
+			if x[i] != y[i] {
+				return false
+			}
+		}
+		return true
+	case Float:
+		return math.Float32bits(float32(x)) == math.Float32bits(float32(b.(Float)))
+	case Double:
+		return math.Float64bits(float64(x)) == math.Float64bits(float64(b.(Double)))
+	}
+	return a == b
+}
@@ -0,0 +1,242 @@
+package nbt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"math"
+	"runtime"
+)
+
+// Tag is a named NBT value, as found at the root of NBT files
+// and within compounds.
+type Tag struct {
+	Name  string
+	Value Value
+}
+
+// Value is implemented by the generic NBT value types below.
+// They allow NBT data to be inspected and rewritten without
+// knowing its structure in advance.
+type Value interface {
+	Kind() TagKind
+}
+
+type (
+	Byte      int8
+	Short     int16
+	Int       int32
+	Long      int64
+	Float     float32
+	Double    float64
+	ByteArray []byte
+	String    string
+	IntArray  []int32
+
+	// Compound keeps its tags in the order they were read,
+	// so that decoding and encoding it again is lossless.
+	Compound []Tag
+)
+
+// List is a list of unnamed values, all of the kind Elem.
+type List struct {
+	Elem   TagKind
+	Values []Value
+}
+
+func (Byte) Kind() TagKind      { return TagByte }
+func (Short) Kind() TagKind     { return TagShort }
+func (Int) Kind() TagKind       { return TagInt }
+func (Long) Kind() TagKind      { return TagLong }
+func (Float) Kind() TagKind     { return TagFloat }
+func (Double) Kind() TagKind    { return TagDouble }
+func (ByteArray) Kind() TagKind { return TagByteArray }
+func (String) Kind() TagKind    { return TagString }
+func (IntArray) Kind() TagKind  { return TagIntArray }
+func (Compound) Kind() TagKind  { return TagCompound }
+func (*List) Kind() TagKind     { return TagList }
+
+// Get returns the value of the tag with the given name,
+// or nil if there is no such tag.
+func (c Compound) Get(name string) Value {
+	for _, t := range c {
+		if t.Name == name {
+			return t.Value
+		}
+	}
+	return nil
+}
+
+var (
+	ErrNoValue       = errors.New("NBT: tag has no value")
+	ErrListElemMixed = errors.New("NBT: list element kind mismatch")
+	ErrLength        = errors.New("NBT: invalid array or list length")
+)
+
+// DecodeTag decodes the named root tag from uncompressed NBT data.
+func DecodeTag(b []byte) (t Tag, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(runtime.Error); ok {
+				panic(r)
+			}
+			err = r.(error)
+		}
+	}()
+	c := decoder{buf: b}
+	k := c.Kind()
+	if k == TagEnd {
+		return t, ErrNoValue
+	}
+	t.Name = c.String()
+	t.Value = c.value(k)
+	return
+}
+
+func (c *decoder) value(k TagKind) Value {
+	switch k {
+	case TagByte:
+		return Byte(c.Uint(k))
+	case TagShort:
+		return Short(c.Uint(k))
+	case TagInt:
+		return Int(c.Uint(k))
+	case TagLong:
+		return Long(c.Uint(k))
+	case TagFloat:
+		return Float(c.Float(k))
+	case TagDouble:
+		return Double(c.Float(k))
+	case TagByteArray:
+		l := c.length(1)
+		b := make(ByteArray, l)
+		copy(b, c.Get(l))
+		return b
+	case TagString:
+		return String(c.String())
+	case TagIntArray:
+		l := c.length(4)
+		v := make(IntArray, l)
+		for i := range v {
+			v[i] = int32(c.Uint(TagInt))
+		}
+		return v
+	case TagList:
+		ek := c.Kind()
+		l := c.length(1)
+		v := &List{Elem: ek, Values: make([]Value, l)}
+		for i := range v.Values {
+			v.Values[i] = c.value(ek)
+		}
+		return v
+	case TagCompound:
+		var v Compound
+		for {
+			ek := c.Kind()
+			if ek == TagEnd {
+				break
+			}
+			n := c.String()
+			v = append(v, Tag{n, c.value(ek)})
+		}
+		if v == nil {
+			v = Compound{}
+		}
+		return v
+	}
+	panic(errKindMismatch(k, "while reading a value"))
+}
+
+// length reads the length of an array or list with elements of at
+// least size bytes, and checks that the rest of the input can hold it.
+func (c *decoder) length(size int) int {
+	l := int64(int32(c.Uint(TagInt)))
+	if l < 0 || l*int64(size) > int64(len(c.buf)-c.p) {
+		panic(ErrLength)
+	}
+	return int(l)
+}
+
+// EncodeTag returns the uncompressed NBT encoding of t.
+func EncodeTag(t Tag) ([]byte, error) {
+	if t.Value == nil {
+		return nil, ErrNoValue
+	}
+	e := &encoder{}
+	e.kind(t.Value.Kind())
+	e.string(t.Name)
+	if err := e.value(t.Value); err != nil {
+		return nil, err
+	}
+	return e.Bytes(), nil
+}
+
+type encoder struct {
+	bytes.Buffer
+}
+
+func (e *encoder) kind(k TagKind) { e.WriteByte(byte(k)) }
+
+func (e *encoder) uint(nbytes int, v uint64) {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], v)
+	e.Write(b[8-nbytes:])
+}
+
+func (e *encoder) string(s string) {
+	e.uint(2, uint64(len(s)))
+	e.WriteString(s)
+}
+
+func (e *encoder) value(v Value) error {
+	switch x := v.(type) {
+	case Byte:
+		e.uint(1, uint64(x))
+	case Short:
+		e.uint(2, uint64(x))
+	case Int:
+		e.uint(4, uint64(x))
+	case Long:
+		e.uint(8, uint64(x))
+	case Float:
+		e.uint(4, uint64(math.Float32bits(float32(x))))
+	case Double:
+		e.uint(8, math.Float64bits(float64(x)))
+	case ByteArray:
+		e.uint(4, uint64(len(x)))
+		e.Write(x)
+	case String:
+		e.string(string(x))
+	case IntArray:
+		e.uint(4, uint64(len(x)))
+		for _, i := range x {
+			e.uint(4, uint64(uint32(i)))
+		}
+	case *List:
+		e.kind(x.Elem)
+		e.uint(4, uint64(len(x.Values)))
+		for _, ev := range x.Values {
+			if ev == nil || ev.Kind() != x.Elem {
+				return ErrListElemMixed
+			}
+			if err := e.value(ev); err != nil {
+				return err
+			}
+		}
+	case Compound:
+		for _, t := range x {
+			if t.Value == nil {
+				return ErrNoValue
+			}
+			e.kind(t.Value.Kind())
+			e.string(t.Name)
+			if err := e.value(t.Value); err != nil {
+				return err
+			}
+		}
+		e.kind(TagEnd)
+	default:
+		return errKindMismatch(TagInvalid, "while writing a value")
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package nbt
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func testTag() Tag {
+	return Tag{"", Compound{
+		{"Data", Compound{
+			{"SpawnX", Int(-12)},
+			{"RandomSeed", Long(-4611686018427387905)},
+			{"LevelName", String("world")},
+			{"Player", Compound{
+				{"Pos", &List{TagDouble, []Value{Double(0.5), Double(64), Double(-3.25)}}},
+				{"Rotation", &List{TagFloat, []Value{Float(0.1), Float(float32(math.Inf(-1)))}}},
+				{"Inventory", &List{TagCompound, []Value{
+					Compound{{"id", Short(276)}, {"Count", Byte(1)}},
+				}}},
+				{"Empty", &List{TagEnd, []Value{}}},
+				{"Data", ByteArray{1, 2, 3}},
+				{"Ints", IntArray{1 << 30, -1}},
+			}},
+		}},
+	}}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	t0 := testTag()
+	b, err := EncodeTag(t0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []Compression{Uncompressed, Gzip, Zlib} {
+		cb, err := Compress(b, c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t1, c1, err := Read(cb)
+		if err != nil {
+			t.Fatal(c, err)
+		}
+		if c1 != c {
+			t.Errorf("compression detected as %s, want %s", c1, c)
+		}
+		if !reflect.DeepEqual(t0, t1) {
+			t.Errorf("%s: decoded tag mismatch: %v", c, Diff(t0.Value, t1.Value))
+		}
+	}
+}
+
+func TestJSON(t *testing.T) {
+	t0 := testTag()
+	b, err := json.Marshal(t0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var t1 Tag
+	if err = json.Unmarshal(b, &t1); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(t0, t1) {
+		t.Errorf("JSON roundtrip mismatch: %v\n%s", Diff(t0.Value, t1.Value), b)
+	}
+}
+
+func TestLookup(t *testing.T) {
+	v := testTag().Value
+	for path, want := range map[string]Value{
+		"Data.SpawnX":                    Int(-12),
+		"Data.Player.Pos[2]":             Double(-3.25),
+		"Data.Player.Inventory[0].id":    Short(276),
+		"Data.Player.Data[1]":            Byte(2),
+		"Data.Player.Inventory[1].id":    nil,
+		"Data.Player.Nonexistent":        nil,
+		"Data.SpawnX.Nonexistent":        nil,
+		"Data.Player.Inventory[x]":       nil,
+		"Data.Player.Inventory[0":        nil,
+		"Data.Player.Inventory[0].Count": Byte(1),
+	} {
+		got, err := Lookup(v, path)
+		if want == nil {
+			if err == nil {
+				t.Errorf("%s: expected error, got %v", path, got)
+			}
+			continue
+		}
+		if err != nil || got != want {
+			t.Errorf("%s: got %v, %v; want %v", path, got, err, want)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a, b := testTag(), testTag()
+	if d := Diff(a.Value, b.Value); len(d) != 0 {
+		t.Fatal("unexpected differences:", d)
+	}
+	p := b.Value.(Compound).Get("Data").(Compound).Get("Player").(Compound)
+	p[0].Value.(*List).Values[1] = Double(70)
+	p[5] = Tag{"Ints", IntArray{1 << 30}}
+	p = append(p, Tag{"New", Byte(1)})
+	b.Value.(Compound)[0].Value.(Compound)[3].Value = p
+	got := Diff(a.Value, b.Value)
+	want := []string{
+		"~ Data.Player.Pos[1]: 64 -> 70",
+		"~ Data.Player.Ints: [2] {1073741824, -1} -> [1] {1073741824}",
+		"+ Data.Player.New: 1",
+	}
+	if len(got) != len(want) {
+		t.Fatal("unexpected differences:", got)
+	}
+	for i := range got {
+		if got[i].String() != want[i] {
+			t.Errorf("got %q, want %q", got[i], want[i])
+		}
+	}
+}
+
+func TestDecodeCorrupt(t *testing.T) {
+	tests := []struct {
+		name string
+		b    []byte
+	}{
+		{"negative byte array", []byte{7, 0, 1, 'a', 0xff, 0xff, 0xff, 0xff}},
+		{"huge byte array", []byte{7, 0, 1, 'a', 0x7f, 0xff, 0xff, 0xff, 1, 2}},
+		{"short int array", []byte{11, 0, 1, 'a', 0, 0, 0, 2, 0, 0, 0, 1}},
+		{"negative list", []byte{9, 0, 1, 'a', 1, 0x80, 0, 0, 0}},
+		{"huge list", []byte{9, 0, 1, 'a', 10, 0x10, 0, 0, 0, 0}},
+		{"truncated", []byte{10, 0, 0, 1, 0, 1, 'a'}},
+	}
+	for _, tt := range tests {
+		if _, err := DecodeTag(tt.b); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
@@ -90,6 +90,7 @@ var mapKind = map[string]TagKind{
 	"list":      TagList,
 	"bytearray": TagByteArray,
 	"intarray":  TagIntArray,
+	"compound":  TagCompound,
 }
 
 func deduceKind(t string, i interface{}) TagKind {
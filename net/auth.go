@@ -13,7 +13,6 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"io"
-	"log"
 	"math/rand"
 	"net/http"
 	"strings"
@@ -96,21 +95,28 @@ type PersistentStore interface {
 // PersistentStore provided by the user. User credentials are never stored
 // on disk, only client and access tokens that can be refreshed.
 type YggAuth struct {
-	store PersistentStore
-	up    UserPassworder
-	info  AuthInfo
+	// Logger, if set, receives log messages.
+	Logger Logger
+
+	store   PersistentStore
+	up      UserPassworder
+	info    AuthInfo
+	loadErr error // error loading info from store
 }
 
 // create
-func NewYggAuth(s PersistentStore, up UserPassworder) Auth {
+func NewYggAuth(s PersistentStore, up UserPassworder) *YggAuth {
 	y := &YggAuth{store: s, up: up}
-	err := y.store.Load(&y.info)
-	if err != nil {
-		log.Println(err)
-	}
+	y.loadErr = y.store.Load(&y.info)
 	return y
 }
 
+func (y *YggAuth) log(level Level, msg string, fields ...interface{}) {
+	if y.Logger != nil {
+		y.Logger.Log(level, msg, fields...)
+	}
+}
+
 func (y *YggAuth) ProfileName() string {
 	if y.info.SelectedProfile != nil {
 		return y.info.SelectedProfile.Name
@@ -122,13 +128,17 @@ func (y *YggAuth) ProfileName() string {
 // refresh the given token. It uses the provided UserPassworder
 // if there are no cached tokens or they cannot be refreshed.
 func (y *YggAuth) Start() error {
+	if y.loadErr != nil {
+		y.log(LevelDebug, "No stored tokens", "err", y.loadErr)
+		y.loadErr = nil
+	}
 	// try validate first
 	if y.Validate() == nil {
 		return nil
 	}
 	// then try refresh
 	if y.Refresh() == nil {
-		log.Println("Access token refreshed.")
+		y.log(LevelInfo, "Access token refreshed")
 		return nil
 	}
 	// else ask the user for her credentials
@@ -141,7 +151,7 @@ func (y *YggAuth) Start() error {
 	if err = y.Authenticate(user, passwd); err != nil {
 		return err
 	}
-	log.Println("Authenticated.")
+	y.log(LevelInfo, "Authenticated", "profile", y.ProfileName())
 	return nil
 }
 
@@ -0,0 +1,227 @@
+package net
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+/*
+
+Capture files record packets sent and received by a Conn.
+
+A capture file starts with the magic string "MCTOYCAP" and a version byte,
+followed by records. Each record is a varint length of the remaining record
+data followed by:
+
+ Field          Encoding
+ =====          ========
+ Time           int64, nanoseconds since the Unix epoch
+ Direction      byte, 0: serverbound, 1: clientbound
+ State          byte, the CxnState the packet was sent in
+ Version        varint, protocol version
+ Payload        rest of the record: packet id and data, decrypted and unframed
+
+*/
+
+const (
+	captureMagic   = "MCTOYCAP"
+	captureVersion = 1
+)
+
+var (
+	ErrCaptureInvalid = errors.New("Capture file invalid")
+	ErrCaptureVersion = errors.New("Capture file version unsupported")
+)
+
+// Direction is the direction a packet travels on the wire.
+type Direction byte
+
+const (
+	Serverbound Direction = 0
+	Clientbound Direction = 1
+)
+
+func (d Direction) String() string {
+	switch d {
+	case Serverbound:
+		return "->"
+	case Clientbound:
+		return "<-"
+	}
+	return "invalid"
+}
+
+// Receiver returns the type of the host receiving packets in direction d.
+func (d Direction) Receiver() proto.HostType {
+	if d == Serverbound {
+		return proto.Server
+	}
+	return proto.Client
+}
+
+// CaptureRecord is a single packet in a capture file.
+type CaptureRecord struct {
+	Time    time.Time
+	Dir     Direction
+	State   proto.CxnState
+	Version int
+	Payload []byte
+}
+
+// Decode decodes the packet stored in the record.
+func (r *CaptureRecord) Decode() (interface{}, error) {
+	hs := proto.GetHostState(r.Dir.Receiver(), r.State)
+	if hs == nil {
+		return nil, ErrStateInvalid
+	}
+	return hs.Decode(r.Payload)
+}
+
+// CaptureWriter writes capture files. It is safe for concurrent use.
+type CaptureWriter struct {
+	mtx sync.Mutex
+	w   io.Writer
+	buf bytes.Buffer
+	err error
+}
+
+// NewCaptureWriter writes the capture file header into w
+// and returns a CaptureWriter to append records to it.
+func NewCaptureWriter(w io.Writer) (*CaptureWriter, error) {
+	if _, err := io.WriteString(w, captureMagic+string([]byte{captureVersion})); err != nil {
+		return nil, err
+	}
+	return &CaptureWriter{w: w}, nil
+}
+
+// CreateCapture creates the capture file fn.
+func CreateCapture(fn string) (*CaptureWriter, error) {
+	f, err := os.Create(fn)
+	if err != nil {
+		return nil, err
+	}
+	cw, err := NewCaptureWriter(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return cw, nil
+}
+
+// Write appends r to the capture. Once writing fails,
+// all subsequent calls return the same error.
+func (cw *CaptureWriter) Write(r *CaptureRecord) error {
+	cw.mtx.Lock()
+	defer cw.mtx.Unlock()
+	if cw.err != nil {
+		return cw.err
+	}
+	var hdr [8 + 2 + 2*binary.MaxVarintLen64]byte
+	binary.BigEndian.PutUint64(hdr[:], uint64(r.Time.UnixNano()))
+	hdr[8], hdr[9] = byte(r.Dir), byte(r.State)
+	n := 10 + binary.PutUvarint(hdr[10:], uint64(r.Version))
+	var lb [binary.MaxVarintLen64]byte
+	cw.buf.Reset()
+	cw.buf.Write(lb[:binary.PutUvarint(lb[:], uint64(n+len(r.Payload)))])
+	cw.buf.Write(hdr[:n])
+	cw.buf.Write(r.Payload)
+	_, cw.err = cw.w.Write(cw.buf.Bytes())
+	return cw.err
+}
+
+// Close closes the underlying writer if it is an io.Closer.
+func (cw *CaptureWriter) Close() error {
+	cw.mtx.Lock()
+	defer cw.mtx.Unlock()
+	if cw.err == nil {
+		cw.err = io.ErrClosedPipe
+	}
+	if c, ok := cw.w.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// CaptureReader reads capture files.
+type CaptureReader struct {
+	r *bufio.Reader
+	c io.Closer
+}
+
+// NewCaptureReader checks the capture file header in r
+// and returns a CaptureReader to read its records.
+func NewCaptureReader(r io.Reader) (*CaptureReader, error) {
+	br := bufio.NewReader(r)
+	hdr := make([]byte, len(captureMagic)+1)
+	if _, err := io.ReadFull(br, hdr); err != nil {
+		return nil, err
+	}
+	if string(hdr[:len(captureMagic)]) != captureMagic {
+		return nil, ErrCaptureInvalid
+	}
+	if hdr[len(captureMagic)] != captureVersion {
+		return nil, ErrCaptureVersion
+	}
+	cr := &CaptureReader{r: br}
+	cr.c, _ = r.(io.Closer)
+	return cr, nil
+}
+
+// OpenCapture opens the capture file fn.
+func OpenCapture(fn string) (*CaptureReader, error) {
+	f, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	cr, err := NewCaptureReader(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return cr, nil
+}
+
+// Next returns the next record of the capture,
+// or io.EOF at the end of the capture.
+func (cr *CaptureReader) Next() (*CaptureRecord, error) {
+	l, err := binary.ReadUvarint(cr.r)
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, int(l))
+	if _, err = io.ReadFull(cr.r, b); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	if len(b) < 11 {
+		return nil, ErrCaptureInvalid
+	}
+	r := &CaptureRecord{
+		Time:  time.Unix(0, int64(binary.BigEndian.Uint64(b))),
+		Dir:   Direction(b[8]),
+		State: proto.CxnState(b[9]),
+	}
+	v, n := binary.Uvarint(b[10:])
+	if n <= 0 {
+		return nil, ErrCaptureInvalid
+	}
+	r.Version, r.Payload = int(v), b[10+n:]
+	return r, nil
+}
+
+// Close closes the underlying reader if it is an io.Closer.
+func (cr *CaptureReader) Close() error {
+	if cr.c != nil {
+		return cr.c.Close()
+	}
+	return nil
+}
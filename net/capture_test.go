@@ -0,0 +1,74 @@
+package net
+
+import (
+	"bytes"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestCapture(t *testing.T) {
+	packets := []struct {
+		dir   Direction
+		state proto.CxnState
+		p     interface{}
+	}{
+		{Serverbound, proto.StateHandshake, &proto.Handshake{ProtocolVersion: 4, ServerAddress: "localhost", ServerPort: 25565, NextState: 2}},
+		{Serverbound, proto.StateLogin, &proto.LoginStart{Name: "Steve"}},
+		{Clientbound, proto.StateLogin, &proto.LoginSuccess{UUID: "uuid", Username: "Steve"}},
+		{Clientbound, proto.StatePlay, &proto.KeepAlive{KeepAliveID: 42}},
+		{Serverbound, proto.StatePlay, &proto.KeepAlive{KeepAliveID: 42}},
+	}
+
+	var buf bytes.Buffer
+	cw, err := NewCaptureWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t0 := time.Now()
+	pbuf := make([]byte, 1024)
+	for i, x := range packets {
+		hs := proto.GetHostState(1-x.dir.Receiver(), x.state)
+		n, err := hs.Encode(pbuf, x.p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = cw.Write(&CaptureRecord{
+			Time:    t0.Add(time.Duration(i) * time.Millisecond),
+			Dir:     x.dir,
+			State:   x.state,
+			Version: proto.ProtocolVersion,
+			Payload: pbuf[:n],
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cr, err := NewCaptureReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, x := range packets {
+		r, err := cr.Next()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !r.Time.Equal(t0.Add(time.Duration(i)*time.Millisecond)) || r.Dir != x.dir ||
+			r.State != x.state || r.Version != proto.ProtocolVersion {
+			t.Errorf("record %d header mismatch: %+v", i, r)
+		}
+		p, err := r.Decode()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(p, x.p) {
+			t.Errorf("record %d packet mismatch: %+v", i, p)
+		}
+	}
+	if _, err := cr.Next(); err != io.EOF {
+		t.Error("expected EOF, got", err)
+	}
+}
@@ -3,13 +3,15 @@ package net
 import (
 	"encoding/json"
 	"errors"
-	"fmt"
 	proto "github.com/tajtiattila/mctoy/protocol"
 	"time"
 )
 
 type ClientConn struct {
 	Conn
+
+	// LoginSuccess is the packet received upon successful Login.
+	LoginSuccess *proto.LoginSuccess
 }
 
 var (
@@ -116,7 +118,7 @@ func (c *ClientConn) Login(auth Auth) error {
 		return err
 	}
 
-	c.state = proto.StateLogin
+	c.SetState(proto.StateLogin)
 
 	err = c.Send(proto.LoginStart{auth.ProfileName()})
 	if err != nil {
@@ -148,28 +150,29 @@ func (c *ClientConn) Login(auth Auth) error {
 
 	switch pkt := p.(type) {
 	case *proto.LoginSuccess:
-		fmt.Println("Login successful")
-		c.state = proto.StatePlay
+		c.log(LevelInfo, "Login successful", "name", pkt.Username, "uuid", pkt.UUID)
+		c.LoginSuccess = pkt
+		c.SetState(proto.StatePlay)
 	case *proto.LoginDisconnect:
-		fmt.Println("LoginDisconnect:", pkt.Reason)
-		err = ErrLoginFailed
+		c.log(LevelWarn, "Login rejected", "reason", pkt.Reason)
+		err = &DisconnectError{Reason: pkt.Reason}
 	default:
-		fmt.Println("Unexpected packet received at login")
+		c.log(LevelError, "Unexpected packet at login", "packet", packetName(p))
 		err = ErrLoginFailed
 	}
 
-	return nil
+	return err
 }
 
 func (c *ClientConn) Handshake(nextstate proto.CxnState) (err error) {
 	err = c.Send(proto.Handshake{
-		ProtocolVersion: 4, // 1.7.2
+		ProtocolVersion: proto.ProtocolVersion,
 		ServerAddress:   c.host,
 		ServerPort:      uint16(c.port),
 		NextState:       uint(nextstate),
 	})
 	if err == nil {
-		c.state = nextstate
+		c.SetState(nextstate)
 	}
 	return
 }
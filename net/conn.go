@@ -2,6 +2,7 @@ package net
 
 import (
 	"bufio"
+	"context"
 	"crypto/aes"
 	"crypto/cipher"
 	"encoding/binary"
@@ -9,28 +10,53 @@ import (
 	"fmt"
 	proto "github.com/tajtiattila/mctoy/protocol"
 	"io"
-	"log"
 	"net"
 	"os"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
 type Conn struct {
-	host   string
-	port   int
-	c      net.Conn
-	r      *bufio.Reader
-	w      io.Writer
-	rbuf   []byte
-	wbuf   []byte
-	wmtx   sync.Mutex
-	state  proto.CxnState
-	pkxi   [2]uint
-	ht     proto.HostType // server:0 client:1
-	logger *log.Logger
+	// first for alignment
+	lastRecv, lastSend int64 // UnixNano, accessed atomically
+
+	host  string
+	port  int
+	c     net.Conn
+	r     *bufio.Reader
+	w     io.Writer
+	rbuf  []byte
+	wbuf  []byte
+	wmtx  sync.Mutex
+	smtx  sync.Mutex // guards state
+	state proto.CxnState
+	pkxi  [2]uint
+	ht    proto.HostType // server:0 client:1
+	id    int
+
+	version int // protocol version
+
+	hmtx      sync.RWMutex      // guards hooks below
+	capture   *CaptureWriter    // records packets if not nil
+	flight    *FlightRecorder   // keeps recent packets if not nil
+	logger    Logger            // receives log messages if not nil
+	pktlog    *PacketTypeFilter // packet types to log
+	intercept []Interceptor     // never modified in place
+	rtimeout  time.Duration     // read timeout if nonzero
+	wtimeout  time.Duration     // write timeout if nonzero
+	autoKA    bool              // respond to KeepAlive packets
+	watchdog  time.Duration     // fail if idle for this long, if nonzero
+	wdRunning bool              // watchdog goroutine started
+	failErr   error             // error failing the connection
+
+	closeOnce sync.Once
+	closeErr  error
+	closed    chan struct{}
+
+	metrics connMetrics
 }
 
 const (
@@ -43,9 +69,41 @@ var (
 	ErrStateInvalid      = errors.New("State invalid")
 )
 
+// Run calls h with the packets received until an error occurs.
 func (c *Conn) Run(h PacketHandler) error {
+	return c.RunContext(context.Background(), h)
+}
+
+// RunContext calls h with the packets received until an error occurs
+// or ctx is done. The connection is closed if ctx is done, and ctx.Err()
+// is returned. A *DisconnectError is returned if the server closes
+// a client connection with a Disconnect packet, after h is called with it.
+func (c *Conn) RunContext(ctx context.Context, h PacketHandler) error {
+	return c.run(ctx, h, c.Recv)
+}
+
+// run calls h with the packets returned by recv.
+func (c *Conn) run(ctx context.Context, h PacketHandler, recv RecvFunc) (err error) {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.Close()
+		case <-done:
+		}
+	}()
+	defer func() {
+		if ctx.Err() != nil {
+			err = ctx.Err()
+			return
+		}
+		if fr := c.flightRecorder(); fr != nil {
+			fr.Dump(err)
+		}
+	}()
 	for {
-		p, err := c.Recv()
+		p, err := recv()
 		if err != nil {
 			return err
 		}
@@ -53,13 +111,87 @@ func (c *Conn) Run(h PacketHandler) error {
 		if err = h.HandlePacket(c, p); err != nil {
 			return err
 		}
+
+		if d, ok := p.(*proto.Disconnect); ok && c.ht == proto.Client {
+			c.log(LevelWarn, "Disconnected", "reason", d.Reason)
+			return &DisconnectError{Reason: d.Reason}
+		}
 	}
 }
 
-const WHATPKT = true
+// DisconnectError is returned when the server closes the connection
+// with a reason.
+type DisconnectError struct {
+	Reason string // JSON chat message
+}
+
+func (e *DisconnectError) Error() string {
+	return "Disconnected: " + e.Reason
+}
+
+// Close closes the connection. It may be called several times
+// and concurrently with other methods.
+func (c *Conn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		c.closeErr = c.c.Close()
+	})
+	return c.closeErr
+}
+
+// Disconnect closes the connection. Connections to game clients are sent
+// reason, a JSON chat message, in the login and play states first.
+// Game clients have no means to tell the reason to the server.
+func (c *Conn) Disconnect(reason string) (err error) {
+	if c.ht == proto.Server {
+		switch c.State() {
+		case proto.StateLogin:
+			err = c.Send(proto.LoginDisconnect{Reason: reason})
+		case proto.StatePlay:
+			err = c.Send(proto.Disconnect{Reason: reason})
+		}
+	}
+	if cerr := c.Close(); err == nil {
+		err = cerr
+	}
+	return
+}
+
+// SetReadTimeout makes receiving a packet fail if it takes longer than d.
+// Zero means no timeout, which is the default.
+func (c *Conn) SetReadTimeout(d time.Duration) {
+	c.hmtx.Lock()
+	c.rtimeout = d
+	c.hmtx.Unlock()
+}
+
+// SetWriteTimeout makes sending a packet fail if it takes longer than d.
+// Zero means no timeout, which is the default.
+func (c *Conn) SetWriteTimeout(d time.Duration) {
+	c.hmtx.Lock()
+	c.wtimeout = d
+	c.hmtx.Unlock()
+}
 
-func (c *Conn) Send(p interface{}) (err error) {
-	hs := proto.GetHostState(c.ht, c.state)
+// SetDeadline sets the read and write deadlines of the connection,
+// overriding timeouts until the next packet.
+func (c *Conn) SetDeadline(t time.Time) error {
+	return c.c.SetDeadline(t)
+}
+
+func (c *Conn) timeouts() (r, w time.Duration) {
+	c.hmtx.RLock()
+	defer c.hmtx.RUnlock()
+	return c.rtimeout, c.wtimeout
+}
+
+// Send sends packet p through the interceptors of c.
+func (c *Conn) Send(p interface{}) error {
+	return c.sendChain(c.interceptors(), p)
+}
+
+func (c *Conn) send(p interface{}) (err error) {
+	hs := proto.GetHostState(c.ht, c.State())
 	if hs == nil {
 		return ErrStateInvalid
 	}
@@ -70,18 +202,69 @@ func (c *Conn) Send(p interface{}) (err error) {
 	if err != nil {
 		return err
 	}
-	if WHATPKT {
-		dumpPacketId("", p, "->")
+	c.logPacket(c.sendDir(), p, n)
+	c.recordFlight(c.sendDir(), c.wbuf[:n], p, nil)
+	if err = c.writePayload(c.wbuf[:n]); err == nil {
+		c.metrics.sent(p)
+	}
+	return
+}
+
+// SendPayload sends the already encoded packet b.
+func (c *Conn) SendPayload(b []byte) error {
+	c.wmtx.Lock()
+	defer c.wmtx.Unlock()
+	c.recordFlight(c.sendDir(), b, nil, nil)
+	return c.writePayload(b)
+}
+
+func (c *Conn) writePayload(b []byte) (err error) {
+	defer func() {
+		if err != nil {
+			err = c.failure(err)
+		}
+	}()
+	if _, wt := c.timeouts(); wt > 0 {
+		c.c.SetWriteDeadline(time.Now().Add(wt))
+	}
+	c.record(c.sendDir(), b)
+	c.metrics.payload(c.sendDir(), c.State(), b)
+	var lb [binary.MaxVarintLen64]byte
+	nl := binary.PutUvarint(lb[:], uint64(len(b)))
+	_, err = c.w.Write(lb[:nl])
+	if err == nil {
+		_, err = c.w.Write(b)
 	}
-	nl := binary.PutUvarint(c.wbuf[n:], uint64(n))
-	_, err = c.w.Write(c.wbuf[n : n+nl])
 	if err == nil {
-		_, err = c.w.Write(c.wbuf[:n])
+		atomic.StoreInt64(&c.lastSend, time.Now().UnixNano())
 	}
 	return
 }
 
-func (c *Conn) Recv() (p interface{}, err error) {
+// Recv receives the next packet through the interceptors of c.
+func (c *Conn) Recv() (interface{}, error) {
+	return c.recvChain(c.interceptors(), c.recv)
+}
+
+func (c *Conn) recv() (p interface{}, err error) {
+	var b []byte
+	if b, err = c.RecvPayload(); err != nil {
+		return
+	}
+	return c.decode(c.State(), b)
+}
+
+// RecvPayload receives the next packet without decoding it.
+// The returned slice is valid until the next call to Recv or RecvPayload.
+func (c *Conn) RecvPayload() (b []byte, err error) {
+	defer func() {
+		if err != nil {
+			err = c.failure(err)
+		}
+	}()
+	if rt, _ := c.timeouts(); rt > 0 {
+		c.c.SetReadDeadline(time.Now().Add(rt))
+	}
 	var l uint64
 	if l, err = binary.ReadUvarint(c.r); err != nil {
 		return
@@ -89,22 +272,178 @@ func (c *Conn) Recv() (p interface{}, err error) {
 	if len(c.rbuf) < int(l) {
 		c.rbuf = make([]byte, len(c.rbuf)+int(l))
 	}
-	b := c.rbuf[:int(l)]
+	b = c.rbuf[:int(l)]
 	if _, err = io.ReadFull(c.r, b); err != nil {
-		return
+		return nil, err
 	}
-	hs := proto.GetHostState(c.ht, c.state)
+	atomic.StoreInt64(&c.lastRecv, time.Now().UnixNano())
+	c.record(1-c.sendDir(), b)
+	c.metrics.payload(1-c.sendDir(), c.State(), b)
+	return
+}
+
+// decode decodes payload b received in state s.
+func (c *Conn) decode(s proto.CxnState, b []byte) (p interface{}, err error) {
+	p, err = c.decodePayload(s, b)
+	c.received(b, p, err)
+	if err == nil {
+		c.receivedKeepAlive(p)
+	}
+	return
+}
+
+// decodePayload decodes payload b received in state s.
+func (c *Conn) decodePayload(s proto.CxnState, b []byte) (p interface{}, err error) {
+	hs := proto.GetHostState(c.ht, s)
 	if hs == nil {
 		return nil, ErrStateInvalid
 	}
 	p, err = hs.Decode(b)
+	if h, ok := p.(*proto.Handshake); ok {
+		c.version = int(h.ProtocolVersion)
+	}
+	return
+}
+
+// received records, logs and counts packet p decoded from payload b.
+func (c *Conn) received(b []byte, p interface{}, err error) {
+	if fr := c.recordFlight(1-c.sendDir(), b, p, err); fr != nil && err != nil {
+		fr.decodeFailed(err)
+	}
 	if err != nil {
-		dumpBytes(b)
+		c.metrics.decodeError()
+		c.log(LevelWarn, "Decoding failed", "dir", 1-c.sendDir(), "bytes", len(b), "err", err)
+	} else {
+		c.metrics.received(p)
+		c.logPacket(1-c.sendDir(), p, len(b))
 	}
-	if WHATPKT {
-		dumpPacketId("<-", p, "")
+}
+
+// State returns the current connection state.
+func (c *Conn) State() proto.CxnState {
+	c.smtx.Lock()
+	defer c.smtx.Unlock()
+	return c.state
+}
+
+// SetState sets the connection state used to encode and decode packets.
+func (c *Conn) SetState(s proto.CxnState) {
+	c.smtx.Lock()
+	c.state = s
+	c.smtx.Unlock()
+	c.metrics.setState(s)
+}
+
+// HostType returns the role of the local host.
+func (c *Conn) HostType() proto.HostType { return c.ht }
+
+// SetCapture makes c record all packets sent and received into cw.
+// Recording is stopped if cw is nil.
+func (c *Conn) SetCapture(cw *CaptureWriter) {
+	c.hmtx.Lock()
+	c.capture = cw
+	c.hmtx.Unlock()
+}
+
+// ID returns the id of c used in log messages,
+// unique within the process.
+func (c *Conn) ID() int { return c.id }
+
+// SetLogger makes c send log messages to l.
+// Logging is disabled if l is nil, which is the default.
+func (c *Conn) SetLogger(l Logger) {
+	c.hmtx.Lock()
+	c.logger = l
+	c.hmtx.Unlock()
+}
+
+// SetPacketLog makes c log packets of the types selected by f,
+// in addition to other messages. Packets are not logged if f is nil.
+func (c *Conn) SetPacketLog(f *PacketTypeFilter) {
+	c.hmtx.Lock()
+	c.pktlog = f
+	c.hmtx.Unlock()
+}
+
+// connCount is the number of Conns created,
+// used for their ids.
+var connCount int32
+
+func (c *Conn) log(level Level, msg string, fields ...interface{}) {
+	c.hmtx.RLock()
+	l := c.logger
+	c.hmtx.RUnlock()
+	if l != nil {
+		f := append([]interface{}{"conn", c.id, "state", proto.CxnStateString(c.State())}, fields...)
+		l.Log(level, msg, f...)
+	}
+}
+
+func (c *Conn) logPacket(d Direction, p interface{}, n int) {
+	c.hmtx.RLock()
+	l, f := c.logger, c.pktlog
+	c.hmtx.RUnlock()
+	if l == nil || f == nil {
+		return
+	}
+	if name := packetName(p); f.Match(name) {
+		l.Log(LevelDebug, proto.PacketString(p),
+			"conn", c.id, "state", proto.CxnStateString(c.State()),
+			"dir", d, "packet", name, "bytes", n)
+	}
+}
+
+// SetFlightRecorder makes c keep its recent packets in fr.
+// Recording is stopped if fr is nil.
+func (c *Conn) SetFlightRecorder(fr *FlightRecorder) {
+	c.hmtx.Lock()
+	c.flight = fr
+	c.hmtx.Unlock()
+}
+
+func (c *Conn) flightRecorder() *FlightRecorder {
+	c.hmtx.RLock()
+	defer c.hmtx.RUnlock()
+	return c.flight
+}
+
+// recordFlight records a packet in the flight recorder of c,
+// and returns the recorder.
+func (c *Conn) recordFlight(d Direction, payload []byte, p interface{}, err error) *FlightRecorder {
+	fr := c.flightRecorder()
+	if fr != nil {
+		fr.Record(&CaptureRecord{
+			Time:    time.Now(),
+			Dir:     d,
+			State:   c.State(),
+			Version: c.version,
+			Payload: payload,
+		}, p, err)
+	}
+	return fr
+}
+
+// sendDir returns the direction of packets sent by c.
+func (c *Conn) sendDir() Direction {
+	if c.ht == proto.Client {
+		return Serverbound
+	}
+	return Clientbound
+}
+
+func (c *Conn) record(d Direction, payload []byte) {
+	c.hmtx.RLock()
+	cw := c.capture
+	c.hmtx.RUnlock()
+	if cw != nil {
+		cw.Write(&CaptureRecord{
+			Time:    time.Now(),
+			Dir:     d,
+			State:   c.State(),
+			Version: c.version,
+			Payload: payload,
+		})
 	}
-	return
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -118,33 +457,50 @@ func (c *Conn) dial(addr string) error {
 	}
 	c.port = 25565
 	var err error
-	if len(v) > 0 {
+	if len(v) > 1 {
 		c.port, err = strconv.Atoi(v[1])
 		if err != nil {
 			return ErrServerAddrInvalid
 		}
 	}
 
-	c.c, err = net.Dial("tcp", fmt.Sprintf("%s:%d", c.host, c.port))
+	nc, err := net.Dial("tcp", fmt.Sprintf("%s:%d", c.host, c.port))
 	if err != nil {
 		return err
 	}
+	c.init(nc, proto.Client)
+	return nil
+}
+
+// NewConn returns a Conn using nc for transport, in handshake state.
+// Argument ht is the role of the local host, use proto.Server
+// for connections accepted from game clients.
+func NewConn(nc net.Conn, ht proto.HostType) *Conn {
+	c := new(Conn)
+	c.init(nc, ht)
+	return c
+}
+
+func (c *Conn) init(nc net.Conn, ht proto.HostType) {
+	c.c = nc
+	c.closed = make(chan struct{})
+	c.lastRecv = time.Now().UnixNano()
 	c.rbuf = make([]byte, connBufLen)
 	c.wbuf = make([]byte, connBufLen)
 
-	c.InitIO(nil)
-
-	c.ht = proto.Client
+	c.ht = ht
 	c.state = proto.StateHandshake
+	c.metrics.init(c.state)
 
-	c.logger = log.New(os.Stdout, "cxn", log.LstdFlags)
+	c.InitIO(nil)
+	c.version = proto.ProtocolVersion
 
-	return nil
+	c.id = int(atomic.AddInt32(&connCount, 1))
 }
 
 func (c *Conn) InitIO(secret []byte) {
 	var r io.Reader
-	r, c.w = InitPacketIO(c.c, secret)
+	r, c.w = InitPacketIO(&countingConn{c.c, &c.metrics, 1 - c.sendDir()}, secret)
 	c.r = bufio.NewReader(r)
 }
 
@@ -0,0 +1,59 @@
+package net
+
+import (
+	"context"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"net"
+	"testing"
+	"time"
+)
+
+func connPair(s proto.CxnState) (c, srv *Conn) {
+	cc, sc := net.Pipe()
+	c, srv = NewConn(cc, proto.Client), NewConn(sc, proto.Server)
+	c.SetState(s)
+	srv.SetState(s)
+	return
+}
+
+type nopHandler struct{}
+
+func (nopHandler) HandlePacket(c *Conn, p interface{}) error { return nil }
+
+func TestConnDisconnect(t *testing.T) {
+	c, s := connPair(proto.StatePlay)
+	go s.Disconnect(`{"text":"bye"}`)
+	err := c.Run(nopHandler{})
+	if de, ok := err.(*DisconnectError); !ok || de.Reason != `{"text":"bye"}` {
+		t.Errorf("got %v, want DisconnectError", err)
+	}
+	if _, err = s.Recv(); err == nil {
+		t.Error("connection not closed")
+	}
+}
+
+func TestConnRunContext(t *testing.T) {
+	c, s := connPair(proto.StatePlay)
+	defer s.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		s.Send(proto.KeepAlive{KeepAliveID: 1})
+		cancel()
+	}()
+	if err := c.RunContext(ctx, nopHandler{}); err != context.Canceled {
+		t.Errorf("got %v, want %v", err, context.Canceled)
+	}
+	if err := c.Send(proto.KeepAlive{}); err == nil {
+		t.Error("connection not closed")
+	}
+}
+
+func TestConnReadTimeout(t *testing.T) {
+	c, s := connPair(proto.StatePlay)
+	defer s.Close()
+	c.SetReadTimeout(10 * time.Millisecond)
+	_, err := c.Recv()
+	if ne, ok := err.(net.Error); !ok || !ne.Timeout() {
+		t.Errorf("got %v, want timeout", err)
+	}
+}
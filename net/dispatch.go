@@ -0,0 +1,162 @@
+package net
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+)
+
+// Dispatcher is a PacketHandler calling the listeners registered
+// for the type of each packet. Listeners may be added and removed
+// at any time, including from within listeners.
+//
+// Listeners of a packet type are called in ascending order, those with
+// the same order in the order they were added. Listeners of all packets
+// are called after the ones of the packet type with the same order.
+// Dispatching a packet stops at the first listener returning an error.
+type Dispatcher struct {
+	mtx sync.RWMutex
+	seq int
+	typ map[reflect.Type][]*Listener // listeners by packet type
+	any []*Listener                  // listeners of all packets
+}
+
+// Listener is a callback registered in a Dispatcher.
+type Listener struct {
+	d     *Dispatcher
+	t     reflect.Type // packet type, nil for all packets
+	order int
+	seq   int
+	f     func(c *Conn, p interface{}) error
+}
+
+var (
+	connPtrType     = reflect.TypeOf((*Conn)(nil))
+	errorType       = reflect.TypeOf((*error)(nil)).Elem()
+	emptyIfaceType  = reflect.TypeOf((*interface{})(nil)).Elem()
+	packetHandlerFn = reflect.TypeOf(func(*Conn, interface{}) error { return nil })
+)
+
+// NewDispatcher returns a Dispatcher without listeners.
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{typ: make(map[reflect.Type][]*Listener)}
+}
+
+// On adds the listener fn with order 0. Fn must be a function of the form
+//
+//	func(c *Conn, p *proto.JoinGame) error
+//
+// receiving packets of a single type, or
+//
+//	func(c *Conn, p interface{}) error
+//
+// receiving all packets. Packet types are pointers to structs, which
+// may also be types a Pipeline replaces packets with.
+// On panics if fn has a different type.
+func (d *Dispatcher) On(fn interface{}) *Listener {
+	return d.OnOrder(0, fn)
+}
+
+// OnOrder adds the listener fn like On, to be called
+// before listeners with a higher order.
+func (d *Dispatcher) OnOrder(order int, fn interface{}) *Listener {
+	if f, ok := fn.(func(*Conn, interface{}) error); ok {
+		return d.add(nil, order, f)
+	}
+	fv := reflect.ValueOf(fn)
+	ft := fv.Type()
+	if ft.Kind() != reflect.Func || ft.NumIn() != 2 || ft.NumOut() != 1 ||
+		ft.In(0) != connPtrType || ft.Out(0) != errorType {
+		panic(fmt.Sprintf("Dispatcher: invalid listener type %s, want func(*Conn, *proto.X) error", ft))
+	}
+	pt := ft.In(1)
+	if pt == emptyIfaceType {
+		return d.add(nil, order, fv.Convert(packetHandlerFn).Interface().(func(*Conn, interface{}) error))
+	}
+	if pt.Kind() != reflect.Ptr || pt.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("Dispatcher: listener argument %s is not a packet type", pt))
+	}
+	return d.add(pt, order, func(c *Conn, p interface{}) error {
+		r := fv.Call([]reflect.Value{reflect.ValueOf(c), reflect.ValueOf(p)})
+		err, _ := r[0].Interface().(error)
+		return err
+	})
+}
+
+// OnAll adds h as a listener of all packets with order 0.
+func (d *Dispatcher) OnAll(h PacketHandler) *Listener {
+	return d.add(nil, 0, h.HandlePacket)
+}
+
+func (d *Dispatcher) add(t reflect.Type, order int, f func(*Conn, interface{}) error) *Listener {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	d.seq++
+	l := &Listener{d: d, t: t, order: order, seq: d.seq, f: f}
+	if t == nil {
+		d.any = insertListener(d.any, l)
+	} else {
+		d.typ[t] = insertListener(d.typ[t], l)
+	}
+	return l
+}
+
+// Remove removes l from its Dispatcher. Packets already being
+// dispatched may still be delivered to l.
+func (l *Listener) Remove() {
+	d := l.d
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	if l.t == nil {
+		d.any = removeListener(d.any, l)
+		return
+	}
+	if v := removeListener(d.typ[l.t], l); len(v) != 0 {
+		d.typ[l.t] = v
+	} else {
+		delete(d.typ, l.t)
+	}
+}
+
+// HandlePacket calls the listeners of packet p.
+func (d *Dispatcher) HandlePacket(c *Conn, p interface{}) error {
+	d.mtx.RLock()
+	tl, al := d.typ[reflect.TypeOf(p)], d.any
+	d.mtx.RUnlock()
+	// slices are never modified in place, so they can be used unlocked
+	for len(tl) != 0 || len(al) != 0 {
+		var l *Listener
+		if len(al) == 0 || (len(tl) != 0 && tl[0].order <= al[0].order) {
+			l, tl = tl[0], tl[1:]
+		} else {
+			l, al = al[0], al[1:]
+		}
+		if err := l.f(c, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// insertListener returns a copy of v with l inserted.
+func insertListener(v []*Listener, l *Listener) []*Listener {
+	i := sort.Search(len(v), func(i int) bool {
+		return v[i].order > l.order || (v[i].order == l.order && v[i].seq > l.seq)
+	})
+	n := make([]*Listener, 0, len(v)+1)
+	n = append(n, v[:i]...)
+	n = append(n, l)
+	return append(n, v[i:]...)
+}
+
+// removeListener returns a copy of v without l.
+func removeListener(v []*Listener, l *Listener) []*Listener {
+	n := make([]*Listener, 0, len(v))
+	for _, x := range v {
+		if x != l {
+			n = append(n, x)
+		}
+	}
+	return n
+}
@@ -0,0 +1,83 @@
+package net
+
+import (
+	"errors"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"reflect"
+	"testing"
+)
+
+func TestDispatcher(t *testing.T) {
+	d := NewDispatcher()
+	var got []string
+	d.On(func(c *Conn, p *proto.JoinGame) error {
+		got = append(got, "join")
+		return nil
+	})
+	d.OnOrder(-1, func(c *Conn, p *proto.JoinGame) error {
+		got = append(got, "join-first")
+		return nil
+	})
+	any := d.On(func(c *Conn, p interface{}) error {
+		got = append(got, "any "+packetName(p))
+		return nil
+	})
+	var ka *Listener
+	ka = d.On(func(c *Conn, p *proto.KeepAlive) error {
+		got = append(got, "keepalive")
+		ka.Remove()
+		return nil
+	})
+
+	d.HandlePacket(nil, &proto.JoinGame{})
+	d.HandlePacket(nil, &proto.KeepAlive{})
+	d.HandlePacket(nil, &proto.KeepAlive{})
+	any.Remove()
+	d.HandlePacket(nil, &proto.ServerChatMessage{})
+
+	want := []string{"join-first", "join", "any JoinGame", "keepalive", "any KeepAlive", "any KeepAlive"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	e := errors.New("stop")
+	d.OnOrder(-2, func(c *Conn, p *proto.JoinGame) error { return e })
+	got = nil
+	if err := d.HandlePacket(nil, &proto.JoinGame{}); err != e || len(got) != 0 {
+		t.Errorf("error %v, calls %q", err, got)
+	}
+}
+
+func TestDispatcherInvalid(t *testing.T) {
+	for _, fn := range []interface{}{
+		func(c *Conn, p proto.JoinGame) error { return nil },
+		func(c *Conn, p *proto.JoinGame) {},
+		func(p *proto.JoinGame) error { return nil },
+		func(c *Conn, p *string) error { return nil },
+		42,
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%T accepted", fn)
+				}
+			}()
+			NewDispatcher().On(fn)
+		}()
+	}
+}
+
+func TestDispatcherProcessed(t *testing.T) {
+	type chunks struct{ n int }
+	d := NewDispatcher()
+	n := 0
+	d.On(func(c *Conn, p *chunks) error {
+		n += p.n
+		return nil
+	})
+	d.HandlePacket(nil, &chunks{3})
+	d.HandlePacket(nil, &proto.KeepAlive{})
+	if n != 3 {
+		t.Errorf("got %d, want 3", n)
+	}
+}
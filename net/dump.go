@@ -2,10 +2,8 @@ package net
 
 import (
 	"bytes"
-	"encoding/json"
 	"fmt"
 	"io"
-	"os"
 	"reflect"
 )
 
@@ -65,7 +63,7 @@ func (d Dumper) bytes(buf []byte) {
 	}
 	e(true)
 	if sfx != "" {
-		fmt.Println(sfx)
+		fmt.Fprintln(d.l, sfx)
 	}
 }
 
@@ -99,33 +97,14 @@ func (d *DebugWriter) Write(buf []byte) (n int, err error) {
 	return
 }
 
-func dumpJson(i interface{}) string {
-	d, err := json.Marshal(i)
-	if err != nil {
-		return "JsonMarshalError: " + err.Error()
-	}
-	buf := bytes.NewBuffer(nil)
-	err = json.Indent(buf, d, "  ", "  ")
-	if err != nil {
-		return string(d)
-	}
-	return string(buf.Bytes())
-}
-
-func dumpBytes(b []byte) {
-	MakeDumper(os.Stdout).bytes(b)
-}
-
-func dumpPacketId(pre string, p interface{}, suf string) {
-	ts := ""
+// packetName returns the type name of packet p.
+func packetName(p interface{}) string {
 	if p == nil {
-		ts = "nil"
-	} else {
-		rt := reflect.TypeOf(p)
-		for rt.Kind() == reflect.Ptr {
-			rt = rt.Elem()
-		}
-		ts = rt.Name()
+		return "nil"
+	}
+	rt := reflect.TypeOf(p)
+	for rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
 	}
-	fmt.Print(pre, ts, suf, "\n")
+	return rt.Name()
 }
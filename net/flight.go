@@ -0,0 +1,138 @@
+package net
+
+import (
+	"bytes"
+	"fmt"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FlightRecorder keeps the last packets sent and received by a Conn.
+// The packets recorded are written to a file when Conn.Run returns
+// an error, or when a packet fails to decode.
+type FlightRecorder struct {
+	// Dir is the directory dump files are created in.
+	// The current directory is used if Dir is empty.
+	Dir string
+
+	// Prefix is the start of dump file names,
+	// the time of the dump and ".txt" is appended to it.
+	Prefix string
+
+	// OnDump, if set, is called after each dump with the
+	// name of the file and the error writing it, if any.
+	OnDump func(fn string, err error)
+
+	mtx      sync.Mutex
+	recs     []flightRecord // ring buffer
+	next     int            // next record to overwrite in recs
+	full     bool
+	lastDump time.Time // last dump triggered by a decode error
+}
+
+type flightRecord struct {
+	CaptureRecord
+	Packet interface{} // decoded packet, or nil
+	Err    error       // decoding error
+}
+
+// decodeDumpInterval is the minimum time between
+// dumps triggered by decoding errors.
+const decodeDumpInterval = time.Minute
+
+// NewFlightRecorder returns a FlightRecorder
+// keeping the last n packets.
+func NewFlightRecorder(n int) *FlightRecorder {
+	return &FlightRecorder{
+		Prefix: "mctoy-flight",
+		recs:   make([]flightRecord, n),
+	}
+}
+
+// Record records a packet. The payload is copied.
+func (fr *FlightRecorder) Record(r *CaptureRecord, p interface{}, err error) {
+	if len(fr.recs) == 0 {
+		return
+	}
+	fr.mtx.Lock()
+	defer fr.mtx.Unlock()
+	x := &fr.recs[fr.next]
+	x.CaptureRecord = *r
+	x.Payload = append(x.Payload[:0], r.Payload...)
+	x.Packet, x.Err = p, err
+	if fr.next++; fr.next == len(fr.recs) {
+		fr.next, fr.full = 0, true
+	}
+}
+
+// WriteTo writes the packets recorded to w, oldest first.
+// Each packet is written in decoded form if available,
+// followed by a hex dump of its payload.
+func (fr *FlightRecorder) WriteTo(w io.Writer) (int64, error) {
+	fr.mtx.Lock()
+	defer fr.mtx.Unlock()
+	var buf bytes.Buffer
+	d := MakeDumper(&buf)
+	i, n := 0, fr.next
+	if fr.full {
+		i, n = fr.next, len(fr.recs)
+	}
+	for ; n > 0; n-- {
+		r := &fr.recs[i]
+		fmt.Fprintf(&buf, "%s %s %s ", r.Time.Format("15:04:05.000"), r.Dir, proto.CxnStateString(r.State))
+		if r.Err != nil {
+			fmt.Fprintf(&buf, "[%d bytes] %v\n", len(r.Payload), r.Err)
+		} else if r.Packet != nil {
+			fmt.Fprintln(&buf, proto.PacketString(r.Packet))
+		} else {
+			fmt.Fprintf(&buf, "[%d bytes]\n", len(r.Payload))
+		}
+		d.bytes(r.Payload)
+		if i++; i == len(fr.recs) {
+			i = 0
+		}
+	}
+	return buf.WriteTo(w)
+}
+
+// Dump writes the packets recorded into a new file named after
+// the current time, starting with reason, and returns its name.
+func (fr *FlightRecorder) Dump(reason error) (fn string, err error) {
+	now := time.Now()
+	fn = filepath.Join(fr.Dir, fr.Prefix+now.Format("-20060102-150405.000")+".txt")
+	defer func() {
+		if fr.OnDump != nil {
+			fr.OnDump(fn, err)
+		}
+	}()
+	f, err := os.Create(fn)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(f, "%s: %v\n\n", now.Format(time.RFC3339Nano), reason)
+	if _, err = fr.WriteTo(f); err != nil {
+		f.Close()
+		return
+	}
+	err = f.Close()
+	return
+}
+
+// decodeFailed dumps the packets recorded after a decoding error,
+// unless a dump was triggered by one recently.
+func (fr *FlightRecorder) decodeFailed(err error) {
+	fr.mtx.Lock()
+	now := time.Now()
+	skip := now.Sub(fr.lastDump) < decodeDumpInterval
+	if !skip {
+		fr.lastDump = now
+	}
+	fr.mtx.Unlock()
+	if !skip {
+		fr.Dump(err)
+	}
+}
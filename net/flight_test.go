@@ -0,0 +1,77 @@
+package net
+
+import (
+	"bytes"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"io/ioutil"
+	"net"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestFlightRecorder(t *testing.T) {
+	fr := NewFlightRecorder(3)
+	for i := int32(1); i <= 5; i++ {
+		p := &proto.KeepAlive{KeepAliveID: i}
+		fr.Record(&CaptureRecord{Dir: Clientbound, State: proto.StatePlay, Payload: []byte{0, 0, 0, 0, byte(i)}}, p, nil)
+	}
+	var buf bytes.Buffer
+	if _, err := fr.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	s := buf.String()
+	if strings.Contains(s, "KeepAliveID:2}") {
+		t.Error("old packet kept:\n", s)
+	}
+	i3, i5 := strings.Index(s, "KeepAliveID:3}"), strings.Index(s, "KeepAliveID:5}")
+	if i3 < 0 || i5 < i3 {
+		t.Error("recent packets missing or out of order:\n", s)
+	}
+}
+
+func TestFlightRecorderDecodeError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mctoyflight")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	cc, sc := net.Pipe()
+	c := NewConn(cc, proto.Client)
+	c.SetState(proto.StatePlay)
+	fr := NewFlightRecorder(10)
+	fr.Dir = dir
+	var dumps []string
+	fr.OnDump = func(fn string, err error) {
+		if err != nil {
+			t.Error(err)
+		}
+		dumps = append(dumps, fn)
+	}
+	c.SetFlightRecorder(fr)
+
+	s := NewConn(sc, proto.Server)
+	s.SetState(proto.StatePlay)
+	go func() {
+		s.Send(proto.KeepAlive{KeepAliveID: 7})
+		s.SendPayload([]byte{0x7f, 1, 2, 3}) // invalid packet id
+		sc.Close()
+	}()
+	if _, err = c.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.Recv(); err == nil {
+		t.Fatal("invalid packet decoded")
+	}
+	if len(dumps) != 1 {
+		t.Fatalf("got %d dumps, want 1", len(dumps))
+	}
+	b, err := ioutil.ReadFile(dumps[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), "KeepAliveID:7") || !strings.Contains(string(b), "7f 01 02 03") {
+		t.Error("unexpected dump:\n", string(b))
+	}
+}
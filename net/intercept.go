@@ -0,0 +1,92 @@
+package net
+
+// Interceptor wraps sending and receiving packets by a Conn. It applies
+// to Send and Recv, but not to SendPayload and RecvPayload.
+//
+// Interceptors added to a Conn form a chain, the one added first being
+// the outermost. It sees packets sent first, and packets received last.
+type Interceptor interface {
+	// InterceptSend is called with packet p being sent. It may modify p,
+	// pass a different packet to next, or drop p by not calling next.
+	InterceptSend(c *Conn, p interface{}, next SendFunc) error
+
+	// InterceptRecv is called to receive a packet using next. It may
+	// modify or replace the packet received, or drop it by calling
+	// next again.
+	InterceptRecv(c *Conn, next RecvFunc) (interface{}, error)
+}
+
+// SendFunc sends a packet through the rest of an interceptor chain.
+type SendFunc func(p interface{}) error
+
+// RecvFunc receives a packet through the rest of an interceptor chain.
+type RecvFunc func() (interface{}, error)
+
+// InterceptorFuncs is an Interceptor using its functions.
+// Packets are passed on unchanged if a function is nil.
+type InterceptorFuncs struct {
+	Send func(c *Conn, p interface{}, next SendFunc) error
+	Recv func(c *Conn, next RecvFunc) (interface{}, error)
+}
+
+func (f *InterceptorFuncs) InterceptSend(c *Conn, p interface{}, next SendFunc) error {
+	if f.Send == nil {
+		return next(p)
+	}
+	return f.Send(c, p, next)
+}
+
+func (f *InterceptorFuncs) InterceptRecv(c *Conn, next RecvFunc) (interface{}, error) {
+	if f.Recv == nil {
+		return next()
+	}
+	return f.Recv(c, next)
+}
+
+// AddInterceptor adds i to the end of the interceptor chain of c.
+func (c *Conn) AddInterceptor(i Interceptor) {
+	c.hmtx.Lock()
+	defer c.hmtx.Unlock()
+	v := make([]Interceptor, len(c.intercept), len(c.intercept)+1)
+	copy(v, c.intercept)
+	c.intercept = append(v, i)
+}
+
+// RemoveInterceptor removes i from the interceptor chain of c.
+func (c *Conn) RemoveInterceptor(i Interceptor) {
+	c.hmtx.Lock()
+	defer c.hmtx.Unlock()
+	v := make([]Interceptor, 0, len(c.intercept))
+	for _, x := range c.intercept {
+		if x != i {
+			v = append(v, x)
+		}
+	}
+	c.intercept = v
+}
+
+func (c *Conn) interceptors() []Interceptor {
+	c.hmtx.RLock()
+	defer c.hmtx.RUnlock()
+	return c.intercept
+}
+
+func (c *Conn) sendChain(v []Interceptor, p interface{}) error {
+	if len(v) == 0 {
+		return c.send(p)
+	}
+	return v[0].InterceptSend(c, p, func(p interface{}) error {
+		return c.sendChain(v[1:], p)
+	})
+}
+
+// recvChain receives a packet through interceptors v, using recv
+// at the end of the chain.
+func (c *Conn) recvChain(v []Interceptor, recv RecvFunc) (interface{}, error) {
+	if len(v) == 0 {
+		return recv()
+	}
+	return v[0].InterceptRecv(c, func() (interface{}, error) {
+		return c.recvChain(v[1:], recv)
+	})
+}
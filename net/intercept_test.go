@@ -0,0 +1,87 @@
+package net
+
+import (
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestInterceptor(t *testing.T) {
+	cc, sc := net.Pipe()
+	defer cc.Close()
+	c := NewConn(cc, proto.Client)
+	c.SetState(proto.StatePlay)
+	s := NewConn(sc, proto.Server)
+	s.SetState(proto.StatePlay)
+
+	var order []string
+	trace := func(name string) Interceptor {
+		return &InterceptorFuncs{
+			Send: func(c *Conn, p interface{}, next SendFunc) error {
+				order = append(order, name+" send")
+				return next(p)
+			},
+			Recv: func(c *Conn, next RecvFunc) (interface{}, error) {
+				p, err := next()
+				order = append(order, name+" recv")
+				return p, err
+			},
+		}
+	}
+	c.AddInterceptor(trace("outer"))
+	c.AddInterceptor(&InterceptorFuncs{
+		Send: func(c *Conn, p interface{}, next SendFunc) error {
+			if m, ok := p.(proto.ClientChatMessage); ok {
+				m.Message = "rewritten " + m.Message
+				p = m
+			}
+			return next(p)
+		},
+		Recv: func(c *Conn, next RecvFunc) (interface{}, error) {
+			for {
+				p, err := next()
+				if _, ok := p.(*proto.KeepAlive); !ok || err != nil {
+					return p, err
+				}
+			}
+		},
+	})
+	inner := trace("inner")
+	c.AddInterceptor(inner)
+
+	go func() {
+		s.Send(proto.KeepAlive{KeepAliveID: 1})
+		s.Send(proto.ServerChatMessage{JSONData: "hi"})
+		p, _ := s.Recv()
+		s.Send(proto.ServerChatMessage{JSONData: p.(*proto.ClientChatMessage).Message})
+	}()
+
+	p, err := c.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m, ok := p.(*proto.ServerChatMessage); !ok || m.JSONData != "hi" {
+		t.Fatalf("got %#v, want chat message", p)
+	}
+	want := []string{"inner recv", "inner recv", "outer recv"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("got %q, want %q", order, want)
+	}
+
+	c.RemoveInterceptor(inner)
+	order = nil
+	if err = c.Send(proto.ClientChatMessage{Message: "hello"}); err != nil {
+		t.Fatal(err)
+	}
+	if p, err = c.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	if m, ok := p.(*proto.ServerChatMessage); !ok || m.JSONData != "rewritten hello" {
+		t.Errorf("got %#v, want rewritten message", p)
+	}
+	want = []string{"outer send", "outer recv"}
+	if !reflect.DeepEqual(order, want) {
+		t.Errorf("got %q, want %q", order, want)
+	}
+}
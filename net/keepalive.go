@@ -0,0 +1,146 @@
+package net
+
+import (
+	"fmt"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"sync/atomic"
+	"time"
+)
+
+// TimeoutError is returned when the watchdog of a Conn fails the
+// connection because no packets were received for too long.
+type TimeoutError struct {
+	Idle     time.Duration // time since the last packet
+	LastRecv time.Time     // time of the last packet
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("No packets received for %v", e.Idle)
+}
+
+// Timeout reports true, TimeoutError implements net.Error.
+func (e *TimeoutError) Timeout() bool { return true }
+
+func (e *TimeoutError) Temporary() bool { return false }
+
+// SetAutoKeepAlive makes a client Conn echo the KeepAlive packets
+// received from the server, before they are passed to handlers.
+// It is off by default, so that relayed connections are not affected.
+func (c *Conn) SetAutoKeepAlive(on bool) {
+	c.hmtx.Lock()
+	c.autoKA = on
+	c.hmtx.Unlock()
+}
+
+// receivedKeepAlive notes KeepAlive p received for round trip times,
+// and responds to it if enabled. It is called as soon as p is decoded.
+func (c *Conn) receivedKeepAlive(p interface{}) {
+	ka, ok := p.(*proto.KeepAlive)
+	if !ok {
+		return
+	}
+	c.metrics.keepAliveReceived(ka.KeepAliveID)
+	if c.ht != proto.Client {
+		return
+	}
+	c.hmtx.RLock()
+	on := c.autoKA
+	c.hmtx.RUnlock()
+	if !on {
+		return
+	}
+	if err := c.Send(*ka); err != nil {
+		c.log(LevelWarn, "KeepAlive response failed", "err", err)
+	}
+}
+
+// RTT returns the round trip time of the connection. It is measured
+// with the KeepAlive packets sent by c if there are any, otherwise
+// it is the latency of the player reported by the server.
+func (c *Conn) RTT() time.Duration {
+	m := &c.metrics
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.rtt != 0 {
+		return m.rtt
+	}
+	return m.ping
+}
+
+// LastRecv returns the time the last packet was received,
+// or the time c was created if none were received yet.
+func (c *Conn) LastRecv() time.Time {
+	return time.Unix(0, atomic.LoadInt64(&c.lastRecv))
+}
+
+// LastSend returns the time the last packet was sent,
+// or the zero time if none were sent yet.
+func (c *Conn) LastSend() time.Time {
+	t := atomic.LoadInt64(&c.lastSend)
+	if t == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, t)
+}
+
+// SetWatchdog makes c fail if no packets are received for d. The
+// connection is closed, and pending and later calls sending and receiving
+// packets return a *TimeoutError. The watchdog is stopped if d is zero.
+func (c *Conn) SetWatchdog(d time.Duration) {
+	c.hmtx.Lock()
+	c.watchdog = d
+	start := d > 0 && !c.wdRunning
+	if start {
+		c.wdRunning = true
+	}
+	c.hmtx.Unlock()
+	if start {
+		go c.runWatchdog()
+	}
+}
+
+func (c *Conn) runWatchdog() {
+	for {
+		c.hmtx.Lock()
+		d := c.watchdog
+		if d <= 0 {
+			c.wdRunning = false
+		}
+		c.hmtx.Unlock()
+		if d <= 0 {
+			return
+		}
+		last := c.LastRecv()
+		idle := time.Since(last)
+		if idle >= d {
+			c.fail(&TimeoutError{Idle: idle, LastRecv: last})
+			return
+		}
+		select {
+		case <-c.closed:
+			return
+		case <-time.After(d - idle):
+		}
+	}
+}
+
+// fail closes the connection with err.
+func (c *Conn) fail(err error) {
+	c.hmtx.Lock()
+	if c.failErr == nil {
+		c.failErr = err
+	}
+	c.hmtx.Unlock()
+	c.log(LevelWarn, "Connection failed", "err", err)
+	c.Close()
+}
+
+// failure returns the error failing the connection if any, otherwise err.
+func (c *Conn) failure(err error) error {
+	c.hmtx.RLock()
+	defer c.hmtx.RUnlock()
+	if c.failErr != nil {
+		return c.failErr
+	}
+	return err
+}
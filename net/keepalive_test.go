@@ -0,0 +1,61 @@
+package net
+
+import (
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"testing"
+	"time"
+)
+
+func TestAutoKeepAlive(t *testing.T) {
+	c, s := connPair(proto.StatePlay)
+	defer c.Close()
+	defer s.Close()
+	c.SetAutoKeepAlive(true)
+	t0 := c.LastRecv()
+
+	errc := make(chan error, 1)
+	go func() {
+		if err := s.Send(proto.KeepAlive{KeepAliveID: 9}); err != nil {
+			errc <- err
+			return
+		}
+		p, err := s.Recv()
+		if ka, ok := p.(*proto.KeepAlive); err == nil && (!ok || ka.KeepAliveID != 9) {
+			t.Errorf("got %#v, want KeepAlive echo", p)
+		}
+		errc <- err
+	}()
+	p, err := c.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := p.(*proto.KeepAlive); !ok {
+		t.Errorf("got %#v, want KeepAlive", p)
+	}
+	if err = <-errc; err != nil {
+		t.Fatal(err)
+	}
+	if !c.LastRecv().After(t0) || c.LastSend().IsZero() {
+		t.Errorf("last seen times not updated")
+	}
+	if s.RTT() <= 0 {
+		t.Errorf("round trip not measured")
+	}
+}
+
+func TestWatchdog(t *testing.T) {
+	c, s := connPair(proto.StatePlay)
+	defer s.Close()
+	c.SetWatchdog(20 * time.Millisecond)
+	_, err := c.Recv()
+	te, ok := err.(*TimeoutError)
+	if !ok {
+		t.Fatalf("got %v, want TimeoutError", err)
+	}
+	if te.Idle < 20*time.Millisecond {
+		t.Errorf("failed after %v", te.Idle)
+	}
+	if err = c.Send(proto.KeepAlive{}); err != te {
+		t.Errorf("send: got %v, want TimeoutError", err)
+	}
+}
@@ -0,0 +1,169 @@
+package net
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Level is the severity of a log message.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	case LevelError:
+		return "ERROR"
+	}
+	return fmt.Sprint("LEVEL", int(l))
+}
+
+// ParseLevel returns the level named s, eg. "info".
+func ParseLevel(s string) (Level, error) {
+	for l := LevelDebug; l <= LevelError; l++ {
+		if strings.EqualFold(s, l.String()) {
+			return l, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid log level %q", s)
+}
+
+// Logger receives log messages. Fields are alternating keys and values
+// describing the message, keys are strings.
+//
+// Messages logged by a Conn have the fields "conn" with the connection id
+// and "state" with the connection state. Packets sent and received are
+// logged at LevelDebug with the fields "dir", "packet" and "bytes".
+type Logger interface {
+	Log(level Level, msg string, fields ...interface{})
+}
+
+// LoggerFunc is a function implementing Logger.
+type LoggerFunc func(level Level, msg string, fields ...interface{})
+
+func (f LoggerFunc) Log(level Level, msg string, fields ...interface{}) {
+	f(level, msg, fields...)
+}
+
+// TextLogger is a Logger writing a line of text for each message
+// at or above Level, eg.
+//
+//	15:04:05.000 INFO Login successful conn=1 state=Login
+type TextLogger struct {
+	Level Level
+
+	mtx sync.Mutex
+	w   io.Writer
+}
+
+// NewTextLogger returns a TextLogger writing messages into w.
+func NewTextLogger(w io.Writer, level Level) *TextLogger {
+	return &TextLogger{Level: level, w: w}
+}
+
+func (l *TextLogger) Log(level Level, msg string, fields ...interface{}) {
+	if level < l.Level {
+		return
+	}
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "%s %s %s", time.Now().Format("15:04:05.000"), level, msg)
+	for i := 0; i < len(fields); i += 2 {
+		var v interface{} = "<missing>"
+		if i+1 < len(fields) {
+			v = fields[i+1]
+		}
+		s := fmt.Sprint(v)
+		if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+			s = fmt.Sprintf("%q", s)
+		}
+		fmt.Fprintf(&buf, " %v=%s", fields[i], s)
+	}
+	buf.WriteByte('\n')
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	l.w.Write(buf.Bytes())
+}
+
+// PacketTypeFilter selects the packet types logged. It can be changed
+// while in use, and implements flag.Value. The zero value selects none.
+type PacketTypeFilter struct {
+	mtx   sync.RWMutex
+	all   bool
+	types map[string]bool // exceptions if all is set
+	spec  string
+}
+
+// NewPacketTypeFilter returns a filter set up using spec.
+func NewPacketTypeFilter(spec string) (*PacketTypeFilter, error) {
+	f := new(PacketTypeFilter)
+	if err := f.Set(spec); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Set sets the packet types selected. Spec is a comma separated list of
+// packet type names. The name "*" selects all packets, and names prefixed
+// with "-" are excluded, eg. "*,-KeepAlive,-MapChunkBulk".
+// An empty spec selects none.
+func (f *PacketTypeFilter) Set(spec string) error {
+	all, types := false, make(map[string]bool)
+	for _, n := range strings.Split(spec, ",") {
+		n = strings.TrimSpace(n)
+		if n == "" {
+			continue
+		}
+		if n == "*" {
+			all = true
+			continue
+		}
+		exclude := strings.HasPrefix(n, "-")
+		n = strings.TrimPrefix(n, "-")
+		if _, err := packetType(n); err != nil {
+			return err
+		}
+		types[n] = !exclude
+	}
+	for n, incl := range types {
+		if incl == all {
+			// redundant
+			delete(types, n)
+		}
+	}
+	f.mtx.Lock()
+	f.all, f.types, f.spec = all, types, spec
+	f.mtx.Unlock()
+	return nil
+}
+
+func (f *PacketTypeFilter) String() string {
+	if f == nil {
+		return ""
+	}
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+	return f.spec
+}
+
+// Match reports whether packet type name is selected.
+func (f *PacketTypeFilter) Match(name string) bool {
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+	_, exc := f.types[name]
+	return f.all != exc
+}
@@ -0,0 +1,82 @@
+package net
+
+import (
+	"bytes"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestPacketTypeFilter(t *testing.T) {
+	var f PacketTypeFilter
+	if f.Match("KeepAlive") {
+		t.Error("zero filter matched")
+	}
+	tests := []struct {
+		spec     string
+		match    []string
+		nomatch  []string
+		hasError bool
+	}{
+		{"KeepAlive", []string{"KeepAlive"}, []string{"SpawnMob"}, false},
+		{"*,-KeepAlive", []string{"SpawnMob"}, []string{"KeepAlive"}, false},
+		{"", nil, []string{"KeepAlive"}, false},
+		{"NoSuchPacket", nil, nil, true},
+	}
+	for _, tt := range tests {
+		err := f.Set(tt.spec)
+		if (err != nil) != tt.hasError {
+			t.Errorf("%q: unexpected error %v", tt.spec, err)
+			continue
+		}
+		for _, n := range tt.match {
+			if !f.Match(n) {
+				t.Errorf("%q doesn't match %s", tt.spec, n)
+			}
+		}
+		for _, n := range tt.nomatch {
+			if f.Match(n) {
+				t.Errorf("%q matches %s", tt.spec, n)
+			}
+		}
+	}
+}
+
+func TestConnLogger(t *testing.T) {
+	cc, sc := net.Pipe()
+	c := NewConn(cc, proto.Client)
+	c.SetState(proto.StatePlay)
+	s := NewConn(sc, proto.Server)
+	s.SetState(proto.StatePlay)
+
+	var buf bytes.Buffer
+	c.SetLogger(NewTextLogger(&buf, LevelDebug))
+	f, err := NewPacketTypeFilter("*,-KeepAlive")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.SetPacketLog(f)
+
+	go func() {
+		s.Send(proto.KeepAlive{KeepAliveID: 1})
+		s.Send(proto.ServerChatMessage{JSONData: "hi"})
+		sc.Close()
+	}()
+	for i := 0; i < 2; i++ {
+		if _, err := c.Recv(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	out := buf.String()
+	if strings.Contains(out, "KeepAlive") {
+		t.Error("filtered packet logged:\n", out)
+	}
+	want := "packet=ServerChatMessage bytes="
+	if !strings.Contains(out, want) || !strings.Contains(out, "DEBUG ServerChatMessage{") {
+		t.Error("packet not logged:\n", out)
+	}
+	if !strings.Contains(out, " state=Play ") {
+		t.Error("state not logged:\n", out)
+	}
+}
@@ -0,0 +1,370 @@
+package net
+
+import (
+	"bufio"
+	"encoding/binary"
+	"expvar"
+	"fmt"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// PacketStats are the number of packets of a type,
+// and the total size of their payloads.
+type PacketStats struct {
+	Count uint64
+	Bytes uint64
+}
+
+// Metrics is a snapshot of the metrics of a Conn.
+// Arrays of two elements are indexed by Direction.
+type Metrics struct {
+	Conn   int       // connection id
+	Player string    // player name, if logged in
+	Time   time.Time // time of the snapshot
+
+	// Packets sent and received, by packet type name.
+	Packets [2]map[string]PacketStats
+
+	// Bytes of packets including their length,
+	// before encryption and after it on the wire.
+	Plain, Wire [2]uint64
+
+	// Packets failed to decode.
+	DecodeErrors uint64
+
+	// KeepAliveRTT is the round trip time of the last keepalive
+	// sent and echoed. ServerPing is the latency of the player
+	// reported by the server in the player list.
+	KeepAliveRTT time.Duration
+	ServerPing   time.Duration
+
+	// Time spent in each connection state.
+	StateTime [4]time.Duration
+}
+
+// connMetrics collects the metrics of a Conn.
+type connMetrics struct {
+	mtx          sync.Mutex
+	player       string
+	packets      [2]map[string]PacketStats
+	plain, wire  [2]uint64
+	decodeErrors uint64
+	keepAlive    map[int32]time.Time // keepalives sent waiting for echo
+	echo         map[int32]bool      // keepalives received, sent back as echo
+	rtt, ping    time.Duration
+	state        proto.CxnState
+	stateSince   time.Time
+	stateTime    [4]time.Duration
+}
+
+// maxKeepAliveWait is the number of keepalives
+// waiting for echo tracked.
+const maxKeepAliveWait = 16
+
+func (m *connMetrics) init(s proto.CxnState) {
+	m.packets = [2]map[string]PacketStats{
+		make(map[string]PacketStats),
+		make(map[string]PacketStats),
+	}
+	m.keepAlive = make(map[int32]time.Time)
+	m.echo = make(map[int32]bool)
+	m.state, m.stateSince = s, time.Now()
+}
+
+// payload counts a packet in direction d of state s with payload b.
+func (m *connMetrics) payload(d Direction, s proto.CxnState, b []byte) {
+	name := "unknown"
+	if hs := proto.GetHostState(d.Receiver(), s); hs != nil {
+		if id, n := binary.Uvarint(b); n > 0 && id < uint64(len(hs.Recv)) && hs.Recv[id] != nil {
+			name = hs.Recv[id].Rt.Name()
+		}
+	}
+	var lb [binary.MaxVarintLen64]byte
+	nl := binary.PutUvarint(lb[:], uint64(len(b)))
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	ps := m.packets[d][name]
+	ps.Count++
+	ps.Bytes += uint64(len(b))
+	m.packets[d][name] = ps
+	m.plain[d] += uint64(nl + len(b))
+}
+
+func (m *connMetrics) wireBytes(d Direction, n int) {
+	m.mtx.Lock()
+	m.wire[d] += uint64(n)
+	m.mtx.Unlock()
+}
+
+func (m *connMetrics) decodeError() {
+	m.mtx.Lock()
+	m.decodeErrors++
+	m.mtx.Unlock()
+}
+
+func (m *connMetrics) setState(s proto.CxnState) {
+	now := time.Now()
+	m.mtx.Lock()
+	if int(m.state) < len(m.stateTime) {
+		m.stateTime[m.state] += now.Sub(m.stateSince)
+	}
+	m.state, m.stateSince = s, now
+	m.mtx.Unlock()
+}
+
+// sent notes packet p sent.
+func (m *connMetrics) sent(p interface{}) {
+	var id int32
+	switch x := p.(type) {
+	case *proto.KeepAlive:
+		id = x.KeepAliveID
+	case proto.KeepAlive:
+		id = x.KeepAliveID
+	case *proto.LoginSuccess:
+		m.received(x)
+		return
+	case proto.LoginSuccess:
+		m.received(&x)
+		return
+	default:
+		return
+	}
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.echo[id] {
+		// echo of a keepalive of the peer
+		delete(m.echo, id)
+		return
+	}
+	if len(m.keepAlive) >= maxKeepAliveWait {
+		// drop oldest
+		var oid int32
+		var ot time.Time
+		for i, t := range m.keepAlive {
+			if ot.IsZero() || t.Before(ot) {
+				oid, ot = i, t
+			}
+		}
+		delete(m.keepAlive, oid)
+	}
+	m.keepAlive[id] = time.Now()
+}
+
+// keepAliveReceived notes KeepAlive id received, the echo of one sent
+// or one of the peer to be echoed.
+func (m *connMetrics) keepAliveReceived(id int32) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if t, ok := m.keepAlive[id]; ok {
+		m.rtt = time.Since(t)
+		delete(m.keepAlive, id)
+		return
+	}
+	if len(m.echo) >= maxKeepAliveWait {
+		m.echo = make(map[int32]bool)
+	}
+	m.echo[id] = true
+}
+
+// received notes packet p received.
+func (m *connMetrics) received(p interface{}) {
+	switch x := p.(type) {
+	case *proto.LoginSuccess:
+		m.mtx.Lock()
+		m.player = x.Username
+		m.mtx.Unlock()
+	case *proto.PlayerListItem:
+		m.mtx.Lock()
+		if x.Online && m.player != "" && x.PlayerName == m.player {
+			m.ping = time.Duration(x.Ping) * time.Millisecond
+		}
+		m.mtx.Unlock()
+	}
+}
+
+func (m *connMetrics) snapshot(id int) *Metrics {
+	now := time.Now()
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	x := &Metrics{
+		Conn:         id,
+		Player:       m.player,
+		Time:         now,
+		Plain:        m.plain,
+		Wire:         m.wire,
+		DecodeErrors: m.decodeErrors,
+		KeepAliveRTT: m.rtt,
+		ServerPing:   m.ping,
+		StateTime:    m.stateTime,
+	}
+	if int(m.state) < len(x.StateTime) {
+		x.StateTime[m.state] += now.Sub(m.stateSince)
+	}
+	for d := range m.packets {
+		x.Packets[d] = make(map[string]PacketStats, len(m.packets[d]))
+		for n, ps := range m.packets[d] {
+			x.Packets[d][n] = ps
+		}
+	}
+	return x
+}
+
+// countingConn counts bytes read from and written to
+// the underlying connection. Bytes read travel in direction rd.
+type countingConn struct {
+	c  io.ReadWriter
+	m  *connMetrics
+	rd Direction
+}
+
+func (cc *countingConn) Read(b []byte) (int, error) {
+	n, err := cc.c.Read(b)
+	cc.m.wireBytes(cc.rd, n)
+	return n, err
+}
+
+func (cc *countingConn) Write(b []byte) (int, error) {
+	n, err := cc.c.Write(b)
+	cc.m.wireBytes(1-cc.rd, n)
+	return n, err
+}
+
+// Metrics returns a snapshot of the metrics of c.
+func (c *Conn) Metrics() *Metrics {
+	return c.metrics.snapshot(c.id)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+
+// MetricsRegistry publishes the metrics of a set of Conns.
+type MetricsRegistry struct {
+	mtx   sync.Mutex
+	conns map[*Conn]bool
+}
+
+// NewMetricsRegistry returns an empty MetricsRegistry.
+func NewMetricsRegistry() *MetricsRegistry {
+	return &MetricsRegistry{conns: make(map[*Conn]bool)}
+}
+
+// Add adds c to the connections published.
+func (r *MetricsRegistry) Add(c *Conn) {
+	r.mtx.Lock()
+	r.conns[c] = true
+	r.mtx.Unlock()
+}
+
+// Remove removes c from the connections published.
+func (r *MetricsRegistry) Remove(c *Conn) {
+	r.mtx.Lock()
+	delete(r.conns, c)
+	r.mtx.Unlock()
+}
+
+// Snapshot returns the metrics of the connections, ordered by id.
+func (r *MetricsRegistry) Snapshot() []*Metrics {
+	r.mtx.Lock()
+	v := make([]*Metrics, 0, len(r.conns))
+	for c := range r.conns {
+		v = append(v, c.Metrics())
+	}
+	r.mtx.Unlock()
+	sort.Sort(metricsById(v))
+	return v
+}
+
+type metricsById []*Metrics
+
+func (v metricsById) Len() int           { return len(v) }
+func (v metricsById) Less(i, j int) bool { return v[i].Conn < v[j].Conn }
+func (v metricsById) Swap(i, j int)      { v[i], v[j] = v[j], v[i] }
+
+// Publish publishes the metrics snapshots with expvar under name.
+func (r *MetricsRegistry) Publish(name string) {
+	expvar.Publish(name, expvar.Func(func() interface{} {
+		return r.Snapshot()
+	}))
+}
+
+var dirNames = [2]string{"serverbound", "clientbound"}
+
+// ServeHTTP writes the metrics in the Prometheus text format.
+func (r *MetricsRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	bw := bufio.NewWriter(w)
+	WritePrometheus(bw, r.Snapshot())
+	bw.Flush()
+}
+
+// WritePrometheus writes metrics v in the Prometheus text format to w.
+func WritePrometheus(w io.Writer, v []*Metrics) {
+	type metric struct {
+		name, typ, help string
+		f               func(m *Metrics, lbl string)
+	}
+	var cur string
+	p := func(lbl string, extra string, val interface{}) {
+		if extra != "" {
+			lbl += "," + extra
+		}
+		fmt.Fprintf(w, "%s{%s} %v\n", cur, lbl, val)
+	}
+	metrics := []metric{
+		{"mctoy_packets_total", "counter", "Packets by type and direction.", func(m *Metrics, lbl string) {
+			for d, pm := range m.Packets {
+				for _, n := range sortedKeys(pm) {
+					p(lbl, fmt.Sprintf(`dir="%s",type="%s"`, dirNames[d], n), pm[n].Count)
+				}
+			}
+		}},
+		{"mctoy_packet_bytes_total", "counter", "Packet payload bytes by type and direction.", func(m *Metrics, lbl string) {
+			for d, pm := range m.Packets {
+				for _, n := range sortedKeys(pm) {
+					p(lbl, fmt.Sprintf(`dir="%s",type="%s"`, dirNames[d], n), pm[n].Bytes)
+				}
+			}
+		}},
+		{"mctoy_bytes_total", "counter", "Bytes before encryption (plain) and on the wire.", func(m *Metrics, lbl string) {
+			for d := range m.Plain {
+				p(lbl, fmt.Sprintf(`dir="%s",layer="plain"`, dirNames[d]), m.Plain[d])
+				p(lbl, fmt.Sprintf(`dir="%s",layer="wire"`, dirNames[d]), m.Wire[d])
+			}
+		}},
+		{"mctoy_decode_errors_total", "counter", "Packets failed to decode.", func(m *Metrics, lbl string) {
+			p(lbl, "", m.DecodeErrors)
+		}},
+		{"mctoy_keepalive_rtt_seconds", "gauge", "Round trip time of the last keepalive echoed.", func(m *Metrics, lbl string) {
+			p(lbl, "", m.KeepAliveRTT.Seconds())
+		}},
+		{"mctoy_server_ping_seconds", "gauge", "Player latency reported by the server.", func(m *Metrics, lbl string) {
+			p(lbl, "", m.ServerPing.Seconds())
+		}},
+		{"mctoy_state_seconds_total", "counter", "Time spent in each connection state.", func(m *Metrics, lbl string) {
+			for s, t := range m.StateTime {
+				p(lbl, fmt.Sprintf(`state="%s"`, strings.ToLower(proto.CxnStateString(proto.CxnState(s)))), t.Seconds())
+			}
+		}},
+	}
+	for _, mt := range metrics {
+		cur = mt.name
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", mt.name, mt.help, mt.name, mt.typ)
+		for _, m := range v {
+			mt.f(m, fmt.Sprintf(`conn="%d",player=%q`, m.Conn, m.Player))
+		}
+	}
+}
+
+func sortedKeys(m map[string]PacketStats) []string {
+	v := make([]string, 0, len(m))
+	for k := range m {
+		v = append(v, k)
+	}
+	sort.Strings(v)
+	return v
+}
@@ -0,0 +1,104 @@
+package net
+
+import (
+	"bytes"
+	"fmt"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConnMetrics(t *testing.T) {
+	cc, sc := net.Pipe()
+	c := NewConn(cc, proto.Client)
+	s := NewConn(sc, proto.Server)
+	s.SetState(proto.StateLogin)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Send(proto.LoginSuccess{Username: "Steve"})
+		s.SetState(proto.StatePlay)
+		s.Send(proto.KeepAlive{KeepAliveID: 5})
+		s.Send(proto.PlayerListItem{PlayerName: "Steve", Online: true, Ping: 42})
+		s.SendPayload([]byte{0x7f, 1, 2, 3}) // invalid packet id
+		s.Recv()
+		sc.Close()
+	}()
+	c.SetState(proto.StateLogin)
+	if _, err := c.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	c.SetState(proto.StatePlay)
+	var ka *proto.KeepAlive
+	for i := 0; i < 2; i++ {
+		p, err := c.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if x, ok := p.(*proto.KeepAlive); ok {
+			ka = x
+		}
+	}
+	if _, err := c.Recv(); err == nil {
+		t.Fatal("invalid packet decoded")
+	}
+	if ka == nil {
+		t.Fatal("KeepAlive not received")
+	}
+	if err := c.Send(ka); err != nil {
+		t.Fatal(err)
+	}
+	<-done
+	if rtt := s.Metrics().KeepAliveRTT; rtt <= 0 {
+		t.Errorf("server keepalive RTT %v", rtt)
+	}
+	c.metrics.mtx.Lock()
+	if n := len(c.metrics.keepAlive); n != 0 {
+		t.Errorf("client waiting for echo of %d keepalives", n)
+	}
+	c.metrics.mtx.Unlock()
+
+	m := c.Metrics()
+	if m.Player != "Steve" {
+		t.Errorf("player %q, want Steve", m.Player)
+	}
+	if ps := m.Packets[Clientbound]["KeepAlive"]; ps.Count != 1 || ps.Bytes != 5 {
+		t.Errorf("clientbound KeepAlive %+v", ps)
+	}
+	if ps := m.Packets[Serverbound]["KeepAlive"]; ps.Count != 1 {
+		t.Errorf("serverbound KeepAlive %+v", ps)
+	}
+	if m.Packets[Clientbound]["unknown"].Count != 1 || m.DecodeErrors != 1 {
+		t.Errorf("decode error not counted: %+v", m)
+	}
+	if m.Plain[Serverbound] != 6 || m.Wire[Serverbound] != 6 {
+		t.Errorf("serverbound bytes plain %d wire %d, want 6", m.Plain[Serverbound], m.Wire[Serverbound])
+	}
+	if m.Wire[Clientbound] != m.Plain[Clientbound] {
+		t.Errorf("clientbound bytes plain %d wire %d differ", m.Plain[Clientbound], m.Wire[Clientbound])
+	}
+	if m.ServerPing != 42*time.Millisecond {
+		t.Errorf("server ping %v", m.ServerPing)
+	}
+	if m.StateTime[proto.StatePlay] <= 0 {
+		t.Errorf("no time in play state")
+	}
+
+	r := NewMetricsRegistry()
+	r.Add(c)
+	var buf bytes.Buffer
+	WritePrometheus(&buf, r.Snapshot())
+	out := buf.String()
+	for _, want := range []string{
+		`mctoy_packets_total{conn="` + fmt.Sprint(c.ID()) + `",player="Steve",dir="clientbound",type="KeepAlive"} 1`,
+		`mctoy_decode_errors_total{conn="` + fmt.Sprint(c.ID()) + `",player="Steve"} 1`,
+		"# TYPE mctoy_keepalive_rtt_seconds gauge",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("%s missing from:\n%s", want, out)
+		}
+	}
+}
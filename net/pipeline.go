@@ -0,0 +1,120 @@
+package net
+
+import (
+	"context"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"io"
+	"runtime"
+)
+
+// Pipeline configures RunPipeline.
+type Pipeline struct {
+	// Workers is the number of goroutines decoding and processing
+	// packets, it defaults to the number of CPUs.
+	Workers int
+
+	// Depth is the number of packets received ahead of the one
+	// being handled, it defaults to 64.
+	Depth int
+
+	// Process, if set, is called by the workers with each packet decoded.
+	// It returns the packet, or a replacement passed to the handler
+	// instead, eg. with expensive computations already done.
+	Process func(p interface{}) (interface{}, error)
+}
+
+type pipeItem struct {
+	s    proto.CxnState
+	b    []byte
+	p    interface{} // packet processed
+	err  error
+	dp   interface{} // packet as decoded, before Process
+	derr error
+	done chan struct{}
+}
+
+// RunPipeline is like RunContext, but packets are received, decoded and
+// processed on separate goroutines, so that expensive packets don't delay
+// handling the ones following them. Packets are handled in the order
+// they were received, and pass the interceptors of c on the goroutine
+// calling h. Packets are recorded, logged and counted on that goroutine
+// too, in the order received and as decoded before Process. KeepAlive
+// packets are answered as soon as they are decoded, without waiting for
+// the packets before them.
+//
+// Packets are decoded in the state current when they are received,
+// so RunPipeline is meant for the play state. The receiving goroutine
+// exits when the connection is closed, it should be closed after
+// RunPipeline returns.
+func (c *Conn) RunPipeline(ctx context.Context, h PacketHandler, pl *Pipeline) error {
+	workers, depth := pl.Workers, pl.Depth
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if depth <= 0 {
+		depth = 64
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	order := make(chan *pipeItem, depth)
+	work := make(chan *pipeItem, depth)
+
+	go func() {
+		defer close(work)
+		for {
+			it := &pipeItem{s: c.State(), done: make(chan struct{})}
+			b, err := c.RecvPayload()
+			if err != nil {
+				it.err = err
+				close(it.done)
+			} else {
+				it.b = append([]byte(nil), b...)
+			}
+			select {
+			case order <- it:
+			case <-stop:
+				return
+			}
+			if err != nil {
+				return
+			}
+			work <- it
+		}
+	}()
+
+	for i := 0; i < workers; i++ {
+		go func() {
+			for it := range work {
+				it.p, it.err = c.decodePayload(it.s, it.b)
+				it.dp, it.derr = it.p, it.err
+				if it.err == nil {
+					c.receivedKeepAlive(it.p)
+				}
+				if it.err == nil && pl.Process != nil {
+					if c.flightRecorder() != nil {
+						// Process may modify the packet recorded
+						it.dp, it.derr = c.decodePayload(it.s, it.b)
+					}
+					it.p, it.err = pl.Process(it.p)
+				}
+				close(it.done)
+			}
+		}()
+	}
+
+	next := func() (interface{}, error) {
+		select {
+		case it := <-order:
+			<-it.done
+			if it.b != nil {
+				c.received(it.b, it.dp, it.derr)
+			}
+			return it.p, it.err
+		case <-ctx.Done():
+			return nil, io.EOF
+		}
+	}
+	return c.run(ctx, h, func() (interface{}, error) {
+		return c.recvChain(c.interceptors(), next)
+	})
+}
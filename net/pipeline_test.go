@@ -0,0 +1,132 @@
+package net
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+type recordHandler struct {
+	ids []int32
+	n   int
+}
+
+func (h *recordHandler) HandlePacket(c *Conn, p interface{}) error {
+	h.ids = append(h.ids, p.(*proto.KeepAlive).KeepAliveID)
+	if len(h.ids) == h.n {
+		return errDone
+	}
+	return nil
+}
+
+var errDone = errors.New("done")
+
+func TestRunPipeline(t *testing.T) {
+	c, s := connPair(proto.StatePlay)
+	defer c.Close()
+	defer s.Close()
+	dir, err := ioutil.TempDir("", "mctoyflight")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fr := NewFlightRecorder(200)
+	fr.Dir = dir
+	c.SetFlightRecorder(fr)
+	const n = 100
+	go func() {
+		for i := int32(0); i < n; i++ {
+			if err := s.Send(proto.KeepAlive{KeepAliveID: i}); err != nil {
+				return
+			}
+		}
+	}()
+	pl := &Pipeline{
+		Workers: 4,
+		Process: func(p interface{}) (interface{}, error) {
+			ka := p.(*proto.KeepAlive)
+			// finish out of order
+			time.Sleep(time.Duration(n-ka.KeepAliveID) * 10 * time.Microsecond)
+			ka.KeepAliveID *= 2
+			return ka, nil
+		},
+	}
+	h := &recordHandler{n: n}
+	if err := c.RunPipeline(context.Background(), h, pl); err != errDone {
+		t.Fatal(err)
+	}
+	for i, id := range h.ids {
+		if id != int32(2*i) {
+			t.Fatalf("packet %d: got id %d, want %d", i, id, 2*i)
+		}
+	}
+
+	// the flight recorder keeps the packets in wire order as decoded
+	var buf bytes.Buffer
+	if _, err := fr.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	dump, last := buf.String(), -1
+	for i := 0; i < n; i++ {
+		j := strings.Index(dump, fmt.Sprintf("KeepAliveID:%d}", i))
+		if j < last {
+			t.Fatalf("packet %d missing or out of order in flight recorder:\n%s", i, dump)
+		}
+		last = j
+	}
+	if strings.Contains(dump, fmt.Sprintf("KeepAliveID:%d}", 2*(n-1))) {
+		t.Error("flight recorder shows processed packets")
+	}
+}
+
+func TestRunPipelineKeepAlive(t *testing.T) {
+	c, s := connPair(proto.StatePlay)
+	defer c.Close()
+	defer s.Close()
+	c.SetAutoKeepAlive(true)
+	echoed := make(chan int32, 2)
+	go func() {
+		s.Send(proto.KeepAlive{KeepAliveID: 1})
+		s.Send(proto.KeepAlive{KeepAliveID: 2})
+		for {
+			p, err := s.Recv()
+			if err != nil {
+				return
+			}
+			if ka, ok := p.(*proto.KeepAlive); ok {
+				echoed <- ka.KeepAliveID
+			}
+		}
+	}()
+	pl := &Pipeline{
+		Workers: 2,
+		Process: func(p interface{}) (interface{}, error) {
+			if p.(*proto.KeepAlive).KeepAliveID != 1 {
+				return p, nil
+			}
+			// a slow packet does not delay answering the next one
+			for {
+				select {
+				case id := <-echoed:
+					if id == 2 {
+						return p, nil
+					}
+				case <-time.After(5 * time.Second):
+					t.Error("KeepAlive not answered while processing the one before")
+					return p, nil
+				}
+			}
+		},
+	}
+	h := &recordHandler{n: 2}
+	if err := c.RunPipeline(context.Background(), h, pl); err != errDone {
+		t.Fatal(err)
+	}
+}
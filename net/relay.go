@@ -0,0 +1,128 @@
+package net
+
+import (
+	"errors"
+	"fmt"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"io"
+	"sync"
+	"time"
+)
+
+// Relay forwards packets between a game client and a server.
+//
+// Packets are decoded for logging and to follow state changes, but are
+// forwarded as received unless modified by Filter, so packets that fail
+// to decode are relayed as well.
+type Relay struct {
+	Client *Conn // connection accepted from the game client
+	Server *Conn // connection to the server
+
+	// Filter, if set, may drop, modify, delay or inject packets.
+	Filter PacketFilter
+
+	// Log, if set, receives a line for every packet relayed.
+	Log io.Writer
+
+	lmtx sync.Mutex
+}
+
+var (
+	ErrRelayEncrypted = errors.New("Relay: server requested encryption")
+)
+
+// stateUpdater is implemented by packets that switch the connection state.
+type stateUpdater interface {
+	StateUpdate() proto.CxnState
+}
+
+// Run relays packets until either side fails or closes the connection,
+// and returns the first error encountered. Both connections are closed
+// by the time Run returns.
+func (r *Relay) Run() error {
+	errc := make(chan error, 2)
+	go func() { errc <- r.relay(r.Client, r.Server, Serverbound) }()
+	go func() { errc <- r.relay(r.Server, r.Client, Clientbound) }()
+	err := <-errc
+	r.Client.Close()
+	r.Server.Close()
+	<-errc
+	return err
+}
+
+func (r *Relay) relay(src, dst *Conn, d Direction) error {
+	for {
+		b, err := src.RecvPayload()
+		if err != nil {
+			return err
+		}
+		p, derr := src.decode(src.State(), b)
+		r.log(src.State(), d, p, len(b), derr)
+		if _, ok := p.(*proto.EncryptionRequest); ok {
+			// can't follow the stream once encryption is enabled
+			return ErrRelayEncrypted
+		}
+		var res *FilterResult
+		if r.Filter != nil && p != nil {
+			res = r.Filter.FilterPacket(d, src.State(), p)
+		}
+		if res == nil {
+			res = new(FilterResult)
+		}
+		if res.Delay > 0 {
+			r.logf("  delayed %s", res.Delay)
+			time.Sleep(res.Delay)
+		}
+		switch {
+		case res.Drop:
+			r.logf("  dropped")
+			continue
+		case res.Modified:
+			r.logf("  modified: %s", proto.PacketString(p))
+			err = dst.Send(p)
+		default:
+			err = dst.SendPayload(b)
+		}
+		if err != nil {
+			return err
+		}
+		if su, ok := p.(stateUpdater); ok {
+			s := su.StateUpdate()
+			src.SetState(s)
+			dst.SetState(s)
+		}
+		for _, in := range res.Inject {
+			c := r.Server
+			if in.Dir == Clientbound {
+				c = r.Client
+			}
+			r.logf("  injected %s %s", in.Dir, proto.PacketString(in.Packet))
+			if err = c.Send(in.Packet); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (r *Relay) logf(format string, a ...interface{}) {
+	if r.Log == nil {
+		return
+	}
+	r.lmtx.Lock()
+	defer r.lmtx.Unlock()
+	fmt.Fprintf(r.Log, format+"\n", a...)
+}
+
+func (r *Relay) log(s proto.CxnState, d Direction, p interface{}, n int, err error) {
+	if r.Log == nil {
+		return
+	}
+	r.lmtx.Lock()
+	defer r.lmtx.Unlock()
+	ts := time.Now().Format("15:04:05.000")
+	if err != nil {
+		fmt.Fprintf(r.Log, "%s %s %s [%d bytes] %v\n", ts, d, proto.CxnStateString(s), n, err)
+		return
+	}
+	fmt.Fprintf(r.Log, "%s %s %s %s\n", ts, d, proto.CxnStateString(s), proto.PacketString(p))
+}
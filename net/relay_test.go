@@ -0,0 +1,166 @@
+package net
+
+import (
+	crand "crypto/rand"
+	"crypto/rsa"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"net"
+	"testing"
+)
+
+type testAuth string
+
+func (a testAuth) ProfileName() string { return string(a) }
+func (testAuth) Start() error          { return nil }
+func (testAuth) JoinSession(serverId string, publicKey []byte) (*SessionInfo, error) {
+	secret, err := GenerateSharedSecret()
+	if err != nil {
+		return nil, err
+	}
+	rsacipher, err := NewRSA_PKCS1v15(publicKey)
+	if err != nil {
+		return nil, err
+	}
+	return &SessionInfo{secret, rsacipher}, nil
+}
+
+func newTestClientConn(nc net.Conn) *ClientConn {
+	c := &ClientConn{}
+	c.init(nc, proto.Client)
+	c.host, c.port = "localhost", 25565
+	return c
+}
+
+// testServer accepts a login on nc, then sends a KeepAlive
+// and expects it to be echoed.
+func testServer(t *testing.T, nc net.Conn, key *rsa.PrivateKey) <-chan error {
+	errc := make(chan error, 1)
+	go func() {
+		c := &ServerConn{}
+		c.init(nc, proto.Server)
+		errc <- func() error {
+			if _, err := c.ReadHandshake(); err != nil {
+				return err
+			}
+			name, err := c.AcceptLogin(key)
+			if err != nil {
+				return err
+			}
+			if err = c.LoginSuccess("uuid-"+name, name); err != nil {
+				return err
+			}
+			if err = c.Send(proto.KeepAlive{KeepAliveID: 42}); err != nil {
+				return err
+			}
+			p, err := c.Recv()
+			if err != nil {
+				return err
+			}
+			if ka, ok := p.(*proto.KeepAlive); !ok || ka.KeepAliveID != 42 {
+				t.Errorf("expected KeepAlive echo, got %+v", p)
+			}
+			return nil
+		}()
+	}()
+	return errc
+}
+
+func testClient(t *testing.T, c *ClientConn) {
+	if err := c.Login(testAuth("Steve")); err != nil {
+		t.Fatal(err)
+	}
+	if c.LoginSuccess == nil || c.LoginSuccess.UUID != "uuid-Steve" {
+		t.Fatalf("unexpected login success: %+v", c.LoginSuccess)
+	}
+	p, err := c.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = c.Send(p); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServerConnLogin(t *testing.T) {
+	key, err := rsa.GenerateKey(crand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cc, sc := net.Pipe()
+	errc := testServer(t, sc, key)
+	testClient(t, newTestClientConn(cc))
+	if err = <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestServerConnBadSecret(t *testing.T) {
+	key, err := rsa.GenerateKey(crand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cc, sc := net.Pipe()
+	errc := make(chan error, 1)
+	go func() {
+		s := &ServerConn{}
+		s.init(sc, proto.Server)
+		if _, err := s.ReadHandshake(); err != nil {
+			errc <- err
+			return
+		}
+		_, err := s.AcceptLogin(key)
+		errc <- err
+	}()
+	c := newTestClientConn(cc)
+	if err = c.Handshake(proto.StateLogin); err != nil {
+		t.Fatal(err)
+	}
+	c.SetState(proto.StateLogin)
+	if err = c.Send(proto.LoginStart{Name: "Steve"}); err != nil {
+		t.Fatal(err)
+	}
+	p, err := c.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	erq, ok := p.(*proto.EncryptionRequest)
+	if !ok {
+		t.Fatalf("expected EncryptionRequest, got %+v", p)
+	}
+	rc, err := NewRSA_PKCS1v15(erq.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = c.Send(proto.EncryptionResponse{
+		SharedSecret: rc.Encrypt(make([]byte, 8)),
+		VerifyToken:  rc.Encrypt(erq.VerifyToken),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = <-errc; err != ErrSharedSecretLength {
+		t.Errorf("got %v, want %v", err, ErrSharedSecretLength)
+	}
+}
+
+func TestRelay(t *testing.T) {
+	cc, pc := net.Pipe()
+	ps, sc := net.Pipe()
+	errc := testServer(t, sc, nil)
+	r := &Relay{
+		Client: NewConn(pc, proto.Server),
+		Server: NewConn(ps, proto.Client),
+	}
+	rerrc := make(chan error, 1)
+	go func() { rerrc <- r.Run() }()
+	c := newTestClientConn(cc)
+	testClient(t, c)
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+	if s := r.Client.State(); s != proto.StatePlay {
+		t.Error("relay client state is", proto.CxnStateString(s))
+	}
+	cc.Close()
+	<-rerrc
+}
@@ -0,0 +1,239 @@
+package net
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"io"
+	"net"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Replay feeds the clientbound packets of a capture into a client Conn,
+// and checks the serverbound packets sent through it against the ones
+// recorded in the capture.
+//
+// Serverbound packets are expected in the order they were recorded. Those
+// recorded after a clientbound packet must be sent before the next
+// clientbound packet is received.
+type Replay struct {
+	// Realtime makes the replay deliver packets at their original
+	// pace instead of as fast as possible.
+	Realtime bool
+
+	// Strict makes the replay fail on the first mismatch.
+	Strict bool
+
+	// Ignore, if set, reports serverbound packets exempt from checking,
+	// such as position updates sent on a timer.
+	Ignore func(p interface{}) bool
+
+	// Log, if set, receives a line for every mismatch.
+	Log io.Writer
+
+	c *Conn
+
+	mtx        sync.Mutex
+	recs       []*CaptureRecord
+	next       int       // index of next record to deliver or match
+	rbuf       []byte    // rest of the current clientbound frame
+	wbuf       []byte    // partial serverbound frame
+	t0         time.Time // time of first record delivered
+	start      time.Time // wall clock time of first record delivered
+	mismatches []*ReplayMismatch
+}
+
+// ReplayMismatch is a difference between serverbound packets
+// sent during replay and the ones recorded.
+type ReplayMismatch struct {
+	Index    int         // capture record index, -1 for unexpected packets
+	Expected interface{} // recorded packet, nil if unexpected
+	Got      interface{} // sent packet, nil if missing
+}
+
+func (m *ReplayMismatch) Error() string {
+	switch {
+	case m.Expected == nil:
+		return fmt.Sprintf("Replay: unexpected packet %s", packetName(m.Got))
+	case m.Got == nil:
+		return fmt.Sprintf("Replay: record %d: missing packet %s", m.Index, packetName(m.Expected))
+	}
+	return fmt.Sprintf("Replay: record %d: packet mismatch, expected %s, got %s",
+		m.Index, proto.PacketString(m.Expected), proto.PacketString(m.Got))
+}
+
+// NewReplay reads all records of cr and prepares them for replay.
+func NewReplay(cr *CaptureReader) (*Replay, error) {
+	r := new(Replay)
+	for {
+		rec, err := cr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		r.recs = append(r.recs, rec)
+	}
+	r.c = NewConn(&replayConn{r}, proto.Client)
+	if len(r.recs) != 0 {
+		r.c.SetState(r.recs[0].State)
+		r.c.version = r.recs[0].Version
+	}
+	return r, nil
+}
+
+// Conn returns the Conn the capture is replayed through.
+func (r *Replay) Conn() *Conn { return r.c }
+
+// Run runs h on the replayed Conn until all of the capture is replayed.
+// In strict mode it returns the first mismatch found.
+func (r *Replay) Run(h PacketHandler) error {
+	err := r.c.Run(h)
+	if err == io.EOF {
+		err = nil
+	}
+	return err
+}
+
+// Mismatches returns the mismatches found so far.
+func (r *Replay) Mismatches() []*ReplayMismatch {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return append([]*ReplayMismatch(nil), r.mismatches...)
+}
+
+func (r *Replay) ignored(p interface{}) bool {
+	return r.Ignore != nil && p != nil && r.Ignore(p)
+}
+
+// mismatch records m, and returns it as an error in strict mode.
+func (r *Replay) mismatch(m *ReplayMismatch) error {
+	r.mismatches = append(r.mismatches, m)
+	if r.Log != nil {
+		fmt.Fprintln(r.Log, m.Error())
+	}
+	if r.Strict {
+		return m
+	}
+	return nil
+}
+
+// read fills b with the frame of the next clientbound packet.
+func (r *Replay) read(b []byte) (int, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if len(r.rbuf) == 0 {
+		var rec *CaptureRecord
+		for ; r.next < len(r.recs); r.next++ {
+			rec = r.recs[r.next]
+			if rec.Dir == Clientbound {
+				break
+			}
+			if p, _ := rec.Decode(); !r.ignored(p) {
+				m := &ReplayMismatch{Index: r.next, Expected: p}
+				if err := r.mismatch(m); err != nil {
+					r.next++
+					return 0, err
+				}
+			}
+		}
+		if r.next == len(r.recs) {
+			return 0, io.EOF
+		}
+		r.next++
+		if r.Realtime {
+			r.wait(rec.Time)
+		}
+		r.c.SetState(rec.State)
+		r.c.version = rec.Version
+		var lb [binary.MaxVarintLen64]byte
+		nl := binary.PutUvarint(lb[:], uint64(len(rec.Payload)))
+		r.rbuf = append(append(r.rbuf[:0], lb[:nl]...), rec.Payload...)
+	}
+	n := copy(b, r.rbuf)
+	r.rbuf = r.rbuf[n:]
+	return n, nil
+}
+
+// wait sleeps until it is time to deliver a packet recorded at t.
+func (r *Replay) wait(t time.Time) {
+	if r.start.IsZero() {
+		r.t0, r.start = t, time.Now()
+		return
+	}
+	d := t.Sub(r.t0) - time.Now().Sub(r.start)
+	if d > 0 {
+		r.mtx.Unlock()
+		time.Sleep(d)
+		r.mtx.Lock()
+	}
+}
+
+// write checks the serverbound packets framed in b.
+func (r *Replay) write(b []byte) (int, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.wbuf = append(r.wbuf, b...)
+	for {
+		l, nl := binary.Uvarint(r.wbuf)
+		if nl <= 0 || len(r.wbuf) < nl+int(l) {
+			return len(b), nil
+		}
+		payload := r.wbuf[nl : nl+int(l)]
+		err := r.check(payload)
+		r.wbuf = r.wbuf[nl+int(l):]
+		if err != nil {
+			return len(b), err
+		}
+	}
+}
+
+func (r *Replay) check(payload []byte) error {
+	hs := proto.GetHostState(proto.Server, r.c.State())
+	got, err := hs.Decode(payload)
+	if err != nil {
+		return err
+	}
+	if r.ignored(got) {
+		return nil
+	}
+	for ; r.next < len(r.recs); r.next++ {
+		rec := r.recs[r.next]
+		if rec.Dir != Serverbound {
+			break
+		}
+		exp, _ := rec.Decode()
+		if r.ignored(exp) {
+			continue
+		}
+		r.next++
+		if bytes.Equal(rec.Payload, payload) || reflect.DeepEqual(exp, got) {
+			return nil
+		}
+		return r.mismatch(&ReplayMismatch{Index: r.next - 1, Expected: exp, Got: got})
+	}
+	return r.mismatch(&ReplayMismatch{Index: -1, Got: got})
+}
+
+// replayConn is the net.Conn a Replay feeds its Conn through.
+type replayConn struct {
+	r *Replay
+}
+
+func (c *replayConn) Read(b []byte) (int, error)         { return c.r.read(b) }
+func (c *replayConn) Write(b []byte) (int, error)        { return c.r.write(b) }
+func (c *replayConn) Close() error                       { return nil }
+func (c *replayConn) LocalAddr() net.Addr                { return replayAddr{} }
+func (c *replayConn) RemoteAddr() net.Addr               { return replayAddr{} }
+func (c *replayConn) SetDeadline(t time.Time) error      { return nil }
+func (c *replayConn) SetReadDeadline(t time.Time) error  { return nil }
+func (c *replayConn) SetWriteDeadline(t time.Time) error { return nil }
+
+type replayAddr struct{}
+
+func (replayAddr) Network() string { return "replay" }
+func (replayAddr) String() string  { return "replay" }
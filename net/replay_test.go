@@ -0,0 +1,116 @@
+package net
+
+import (
+	"bytes"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"testing"
+	"time"
+)
+
+type echoHandler struct {
+	chat int
+}
+
+func (h *echoHandler) HandlePacket(c *Conn, pk interface{}) error {
+	switch p := pk.(type) {
+	case *proto.KeepAlive:
+		return c.Send(p)
+	case *proto.ServerChatMessage:
+		h.chat++
+	}
+	return nil
+}
+
+func writeTestCapture(t *testing.T, packets ...interface{}) *CaptureReader {
+	var buf bytes.Buffer
+	cw, err := NewCaptureWriter(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pbuf := make([]byte, 1024)
+	t0 := time.Now()
+	dir := Clientbound
+	for i, p := range packets {
+		if d, ok := p.(Direction); ok {
+			dir = d
+			continue
+		}
+		n, err := proto.GetHostState(1-dir.Receiver(), proto.StatePlay).Encode(pbuf, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cw.Write(&CaptureRecord{
+			Time:    t0.Add(time.Duration(i) * time.Millisecond),
+			Dir:     dir,
+			State:   proto.StatePlay,
+			Version: proto.ProtocolVersion,
+			Payload: pbuf[:n],
+		})
+	}
+	cr, err := NewCaptureReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cr
+}
+
+func TestReplay(t *testing.T) {
+	cr := writeTestCapture(t,
+		Clientbound, &proto.JoinGame{EntityID: 1, LevelType: "default"},
+		&proto.KeepAlive{KeepAliveID: 1},
+		Serverbound, &proto.KeepAlive{KeepAliveID: 1},
+		&proto.PlayerLook{Yaw: 90},
+		Clientbound, &proto.ServerChatMessage{JSONData: `{"text":"hi"}`},
+		&proto.KeepAlive{KeepAliveID: 2},
+		Serverbound, &proto.KeepAlive{KeepAliveID: 2},
+	)
+	r, err := NewReplay(cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Ignore = func(p interface{}) bool {
+		_, ok := p.(*proto.PlayerLook)
+		return ok
+	}
+	r.Strict = true
+	h := new(echoHandler)
+	if err = r.Run(h); err != nil {
+		t.Fatal(err)
+	}
+	if h.chat != 1 {
+		t.Error("chat message not replayed")
+	}
+	if m := r.Mismatches(); len(m) != 0 {
+		t.Error("unexpected mismatches:", m)
+	}
+}
+
+func TestReplayMismatch(t *testing.T) {
+	cr := writeTestCapture(t,
+		Clientbound, &proto.KeepAlive{KeepAliveID: 1},
+		Serverbound, &proto.KeepAlive{KeepAliveID: 2},
+		Clientbound, &proto.KeepAlive{KeepAliveID: 3},
+		Serverbound, &proto.ClientChatMessage{Message: "hello"},
+		&proto.KeepAlive{KeepAliveID: 4},
+	)
+	r, err := NewReplay(cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = r.Run(new(echoHandler)); err != nil {
+		t.Fatal(err)
+	}
+	m := r.Mismatches()
+	if len(m) != 3 {
+		t.Fatal("expected 3 mismatches, got", m)
+	}
+	if m[0].Index != 1 || m[0].Got == nil || m[0].Expected == nil {
+		t.Error("expected packet mismatch, got", m[0])
+	}
+	if m[1].Index != 3 || m[1].Got == nil || m[1].Expected == nil {
+		t.Error("expected packet mismatch, got", m[1])
+	}
+	if m[2].Index != 4 || m[2].Got != nil || m[2].Expected == nil {
+		t.Error("expected missing packet, got", m[2])
+	}
+}
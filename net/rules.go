@@ -0,0 +1,417 @@
+package net
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+/*
+
+Rule files are JSON files describing how packets passing through a Relay
+should be rewritten:
+
+ {"rules": [
+   {"dir": "clientbound", "packet": "Particle", "action": "drop"},
+   {"packet": "ServerChatMessage", "match": {"JSONData": "~secret"},
+    "action": "modify", "set": {"JSONData": "{\"text\":\"censored\"}"}},
+   {"dir": "serverbound", "packet": "KeepAlive", "action": "delay", "delay": "500ms"},
+   {"packet": "ClientChatMessage", "match": {"Message": "=/spam"},
+    "action": "inject", "inject": [
+      {"dir": "serverbound", "packet": "ClientChatMessage", "fields": {"Message": "spam"}}
+   ]}
+ ]}
+
+Rules are applied in order to every packet. Dir is "serverbound",
+"clientbound" or empty for both, packet is the packet type name or empty
+for all packets.
+
+Match selects packets by field values. Keys are field names, nested fields
+and elements can be selected with dots, eg. "HeldItem.Id" or "Records.0".
+Values are compared to the field formatted with fmt.Sprint, and may start
+with an operator:
+
+ Operator   Meaning
+ ========   =======
+ =          equal (default)
+ !          not equal
+ ~          matches regular expression
+ < >        numeric less or greater than
+
+Actions are:
+
+ Action     Effect
+ ======     ======
+ drop       the packet is not forwarded, no further rules are applied
+ modify     fields in "set" are set on the packet
+ delay      the packet, and the ones after it, are held back for "delay"
+ inject     packets in "inject" are sent after the packet
+
+*/
+
+// FilterResult describes how a packet passing through a Relay is altered.
+type FilterResult struct {
+	Drop     bool          // don't forward packet
+	Modified bool          // packet was modified and must be encoded again
+	Delay    time.Duration // forward packet after delay
+	Inject   []Injection   // additional packets to send after packet
+}
+
+// Injection is a packet to be sent in direction Dir.
+type Injection struct {
+	Dir    Direction
+	Packet interface{}
+}
+
+// PacketFilter can alter packets passing through a Relay.
+type PacketFilter interface {
+	// FilterPacket returns how packet p, travelling in direction d
+	// in connection state s, should be altered. It may modify p,
+	// and returns nil if p should be forwarded unchanged.
+	FilterPacket(d Direction, s proto.CxnState, p interface{}) *FilterResult
+}
+
+// RuleSet is a PacketFilter applying rules from a rule file.
+type RuleSet struct {
+	rules []*rule
+}
+
+type rule struct {
+	dirs   [2]bool
+	rt     reflect.Type // nil for any packet
+	match  []predicate
+	action string
+	set    json.RawMessage
+	delay  time.Duration
+	inject []injectRule
+}
+
+type injectRule struct {
+	dir    Direction
+	rt     reflect.Type
+	fields json.RawMessage
+}
+
+type predicate struct {
+	path []string
+	op   byte
+	s    string
+	f    float64
+	re   *regexp.Regexp
+}
+
+type ruleFileJSON struct {
+	Rules []struct {
+		Dir    string            `json:"dir"`
+		Packet string            `json:"packet"`
+		Match  map[string]string `json:"match"`
+		Action string            `json:"action"`
+		Set    json.RawMessage   `json:"set"`
+		Delay  string            `json:"delay"`
+		Inject []struct {
+			Dir    string          `json:"dir"`
+			Packet string          `json:"packet"`
+			Fields json.RawMessage `json:"fields"`
+		} `json:"inject"`
+	} `json:"rules"`
+}
+
+// ParseRules parses the rule file contents in b.
+func ParseRules(b []byte) (*RuleSet, error) {
+	var rf ruleFileJSON
+	if err := json.Unmarshal(b, &rf); err != nil {
+		return nil, err
+	}
+	rs := new(RuleSet)
+	for i, jr := range rf.Rules {
+		r := &rule{action: jr.Action, set: jr.Set}
+		err := func() (err error) {
+			if r.dirs, err = parseDirs(jr.Dir); err != nil {
+				return
+			}
+			if jr.Packet != "" {
+				if r.rt, err = packetType(jr.Packet); err != nil {
+					return
+				}
+			}
+			for path, v := range jr.Match {
+				pr, err := parsePredicate(path, v)
+				if err != nil {
+					return err
+				}
+				r.match = append(r.match, pr)
+			}
+			switch r.action {
+			case "drop":
+			case "modify":
+				if r.rt == nil || len(r.set) == 0 {
+					return errors.New("modify needs packet and set")
+				}
+				err = json.Unmarshal(r.set, reflect.New(r.rt).Interface())
+			case "delay":
+				r.delay, err = time.ParseDuration(jr.Delay)
+			case "inject":
+				for _, ji := range jr.Inject {
+					var ir injectRule
+					d, err := parseDirs(ji.Dir)
+					if err != nil {
+						return err
+					}
+					if d[0] == d[1] {
+						return errors.New("inject needs dir")
+					}
+					if d[1] {
+						ir.dir = Clientbound
+					}
+					if ir.rt, err = packetType(ji.Packet); err != nil {
+						return err
+					}
+					ir.fields = ji.Fields
+					if _, err = ir.packet(); err != nil {
+						return err
+					}
+					r.inject = append(r.inject, ir)
+				}
+			default:
+				err = fmt.Errorf("invalid action %q", r.action)
+			}
+			return
+		}()
+		if err != nil {
+			return nil, fmt.Errorf("Rule %d: %v", i, err)
+		}
+		rs.rules = append(rs.rules, r)
+	}
+	return rs, nil
+}
+
+func parseDirs(s string) (d [2]bool, err error) {
+	switch s {
+	case "":
+		d[Serverbound], d[Clientbound] = true, true
+	case "serverbound":
+		d[Serverbound] = true
+	case "clientbound":
+		d[Clientbound] = true
+	default:
+		err = fmt.Errorf("invalid dir %q", s)
+	}
+	return
+}
+
+func packetType(name string) (reflect.Type, error) {
+	if rt := proto.PacketType(name); rt != nil {
+		return rt, nil
+	}
+	return nil, fmt.Errorf("invalid packet %q", name)
+}
+
+func parsePredicate(path, v string) (pr predicate, err error) {
+	pr.path = strings.Split(path, ".")
+	pr.op = '='
+	if v != "" && strings.IndexByte("=!~<>", v[0]) >= 0 {
+		pr.op, v = v[0], v[1:]
+	}
+	pr.s = v
+	switch pr.op {
+	case '~':
+		pr.re, err = regexp.Compile(v)
+	case '<', '>':
+		pr.f, err = strconv.ParseFloat(v, 64)
+	}
+	return
+}
+
+func (pr *predicate) match(p interface{}) bool {
+	v := reflect.ValueOf(p)
+	for _, n := range pr.path {
+		for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+			if v.IsNil() {
+				return false
+			}
+			v = v.Elem()
+		}
+		switch v.Kind() {
+		case reflect.Struct:
+			v = v.FieldByName(n)
+		case reflect.Slice, reflect.Array:
+			i, err := strconv.Atoi(n)
+			if err != nil || i < 0 || i >= v.Len() {
+				return false
+			}
+			v = v.Index(i)
+		case reflect.Map:
+			k := reflect.New(v.Type().Key()).Elem()
+			if _, err := fmt.Sscan(n, k.Addr().Interface()); err != nil {
+				return false
+			}
+			v = v.MapIndex(k)
+		default:
+			return false
+		}
+		if !v.IsValid() {
+			return false
+		}
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return false
+		}
+		v = v.Elem()
+	}
+	switch pr.op {
+	case '<', '>':
+		var f float64
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			f = float64(v.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			f = float64(v.Uint())
+		case reflect.Float32, reflect.Float64:
+			f = v.Float()
+		default:
+			return false
+		}
+		if pr.op == '<' {
+			return f < pr.f
+		}
+		return f > pr.f
+	}
+	s := fmt.Sprint(v.Interface())
+	switch pr.op {
+	case '!':
+		return s != pr.s
+	case '~':
+		return pr.re.MatchString(s)
+	}
+	return s == pr.s
+}
+
+func (ir *injectRule) packet() (interface{}, error) {
+	pv := reflect.New(ir.rt)
+	if len(ir.fields) != 0 {
+		if err := json.Unmarshal(ir.fields, pv.Interface()); err != nil {
+			return nil, err
+		}
+	}
+	return pv.Interface(), nil
+}
+
+func (r *rule) matches(d Direction, p interface{}) bool {
+	if !r.dirs[d] {
+		return false
+	}
+	if r.rt != nil && reflect.TypeOf(p) != reflect.PtrTo(r.rt) {
+		return false
+	}
+	for i := range r.match {
+		if !r.match[i].match(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// FilterPacket implements PacketFilter.
+func (rs *RuleSet) FilterPacket(d Direction, s proto.CxnState, p interface{}) *FilterResult {
+	var res *FilterResult
+	for _, r := range rs.rules {
+		if !r.matches(d, p) {
+			continue
+		}
+		if res == nil {
+			res = new(FilterResult)
+		}
+		switch r.action {
+		case "drop":
+			res.Drop = true
+			return res
+		case "modify":
+			if json.Unmarshal(r.set, p) == nil {
+				res.Modified = true
+			}
+		case "delay":
+			res.Delay += r.delay
+		case "inject":
+			for i := range r.inject {
+				ip, _ := r.inject[i].packet()
+				res.Inject = append(res.Inject, Injection{r.inject[i].dir, ip})
+			}
+		}
+	}
+	return res
+}
+
+// RuleFile is a PacketFilter applying the rules in a rule file.
+// The file is reloaded when it changes.
+type RuleFile struct {
+	fn string
+
+	// OnError, if set, is called when reloading the file fails.
+	// The previous rules remain in effect in this case.
+	OnError func(err error)
+
+	mtx     sync.Mutex
+	rs      *RuleSet
+	modTime time.Time
+	checked time.Time
+}
+
+// ruleFileCheckInterval is the minimum time between rule file checks.
+const ruleFileCheckInterval = time.Second
+
+// LoadRuleFile loads the rules in file fn.
+func LoadRuleFile(fn string) (*RuleFile, error) {
+	rf := &RuleFile{fn: fn}
+	if err := rf.load(); err != nil {
+		return nil, err
+	}
+	return rf, nil
+}
+
+func (rf *RuleFile) load() error {
+	rf.checked = time.Now()
+	fi, err := os.Stat(rf.fn)
+	if err != nil {
+		return err
+	}
+	if fi.ModTime().Equal(rf.modTime) {
+		return nil
+	}
+	b, err := ioutil.ReadFile(rf.fn)
+	if err != nil {
+		return err
+	}
+	rs, err := ParseRules(b)
+	if err != nil {
+		return err
+	}
+	rf.rs, rf.modTime = rs, fi.ModTime()
+	return nil
+}
+
+// Rules returns the current rules, reloading the file if it has changed.
+func (rf *RuleFile) Rules() *RuleSet {
+	rf.mtx.Lock()
+	defer rf.mtx.Unlock()
+	if time.Now().Sub(rf.checked) >= ruleFileCheckInterval {
+		if err := rf.load(); err != nil && rf.OnError != nil {
+			rf.OnError(err)
+		}
+	}
+	return rf.rs
+}
+
+// FilterPacket implements PacketFilter.
+func (rf *RuleFile) FilterPacket(d Direction, s proto.CxnState, p interface{}) *FilterResult {
+	return rf.Rules().FilterPacket(d, s, p)
+}
@@ -0,0 +1,124 @@
+package net
+
+import (
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testRules = `{"rules": [
+	{"dir": "clientbound", "packet": "Particle", "action": "drop"},
+	{"packet": "ServerChatMessage", "match": {"JSONData": "~secret"},
+	 "action": "modify", "set": {"JSONData": "censored"}},
+	{"packet": "KeepAlive", "match": {"KeepAliveID": ">100"}, "action": "delay", "delay": "10ms"},
+	{"packet": "ClientChatMessage", "match": {"Message": "=/spam"},
+	 "action": "inject", "inject": [
+		{"dir": "serverbound", "packet": "ClientChatMessage", "fields": {"Message": "spam"}}
+	]}
+]}`
+
+func TestRuleSet(t *testing.T) {
+	rs, err := ParseRules([]byte(testRules))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ps := proto.StatePlay
+
+	if res := rs.FilterPacket(Clientbound, ps, &proto.Particle{}); res == nil || !res.Drop {
+		t.Error("clientbound Particle not dropped")
+	}
+	if res := rs.FilterPacket(Serverbound, ps, &proto.Particle{}); res != nil {
+		t.Error("serverbound Particle filtered:", res)
+	}
+
+	cm := &proto.ServerChatMessage{JSONData: "the secret is"}
+	if res := rs.FilterPacket(Clientbound, ps, cm); res == nil || !res.Modified || cm.JSONData != "censored" {
+		t.Errorf("chat not modified: %+v %+v", res, cm)
+	}
+	cm = &proto.ServerChatMessage{JSONData: "hello"}
+	if res := rs.FilterPacket(Clientbound, ps, cm); res != nil || cm.JSONData != "hello" {
+		t.Errorf("chat modified: %+v %+v", res, cm)
+	}
+
+	if res := rs.FilterPacket(Serverbound, ps, &proto.KeepAlive{KeepAliveID: 101}); res == nil || res.Delay != 10*time.Millisecond {
+		t.Error("KeepAlive not delayed:", res)
+	}
+	if res := rs.FilterPacket(Serverbound, ps, &proto.KeepAlive{KeepAliveID: 100}); res != nil {
+		t.Error("KeepAlive delayed:", res)
+	}
+
+	res := rs.FilterPacket(Serverbound, ps, &proto.ClientChatMessage{Message: "/spam"})
+	if res == nil || len(res.Inject) != 1 {
+		t.Fatal("nothing injected:", res)
+	}
+	in := res.Inject[0]
+	if m, ok := in.Packet.(*proto.ClientChatMessage); !ok || m.Message != "spam" || in.Dir != Serverbound {
+		t.Errorf("unexpected injection: %s %+v", in.Dir, in.Packet)
+	}
+}
+
+func TestParseRulesInvalid(t *testing.T) {
+	for _, s := range []string{
+		`{"rules": [{"action": "explode"}]}`,
+		`{"rules": [{"packet": "NoSuchPacket", "action": "drop"}]}`,
+		`{"rules": [{"dir": "sideways", "action": "drop"}]}`,
+		`{"rules": [{"match": {"X": "~("}, "action": "drop"}]}`,
+		`{"rules": [{"match": {"X": "<abc"}, "action": "drop"}]}`,
+		`{"rules": [{"action": "modify", "set": {"X": 1}}]}`,
+		`{"rules": [{"packet": "KeepAlive", "action": "modify", "set": {"KeepAliveID": "x"}}]}`,
+		`{"rules": [{"action": "delay", "delay": "soon"}]}`,
+		`{"rules": [{"action": "inject", "inject": [{"packet": "KeepAlive"}]}]}`,
+	} {
+		if _, err := ParseRules([]byte(s)); err == nil {
+			t.Error("expected error for", s)
+		}
+	}
+}
+
+func TestRuleFileReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mctoyrules")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fn := filepath.Join(dir, "rules.json")
+	write := func(s string, mt time.Time) {
+		if err := ioutil.WriteFile(fn, []byte(s), 0666); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(fn, mt, mt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t0 := time.Now().Add(-time.Hour)
+	write(`{"rules": [{"packet": "KeepAlive", "action": "drop"}]}`, t0)
+	rf, err := LoadRuleFile(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var reloadErr error
+	rf.OnError = func(err error) { reloadErr = err }
+	ka := &proto.KeepAlive{}
+	if res := rf.FilterPacket(Serverbound, proto.StatePlay, ka); res == nil || !res.Drop {
+		t.Fatal("KeepAlive not dropped")
+	}
+
+	write(`{"rules": []}`, t0.Add(time.Minute))
+	rf.checked = time.Time{}
+	if res := rf.FilterPacket(Serverbound, proto.StatePlay, ka); res != nil {
+		t.Fatal("rules not reloaded")
+	}
+
+	write(`{"rules": [`, t0.Add(2*time.Minute))
+	rf.checked = time.Time{}
+	if res := rf.FilterPacket(Serverbound, proto.StatePlay, ka); res != nil {
+		t.Fatal("invalid rules applied")
+	}
+	if reloadErr == nil {
+		t.Error("reload error not reported")
+	}
+}
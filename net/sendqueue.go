@@ -0,0 +1,245 @@
+package net
+
+import (
+	"errors"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"sync"
+	"time"
+)
+
+// Priority is the class of a packet in a SendQueue.
+// Classes with lower values are sent first.
+type Priority int
+
+const (
+	PriorityKeepAlive Priority = iota
+	PriorityMovement
+	PriorityChat
+	PriorityBulk
+
+	numPriorities = iota
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityKeepAlive:
+		return "keepalive"
+	case PriorityMovement:
+		return "movement"
+	case PriorityChat:
+		return "chat"
+	case PriorityBulk:
+		return "bulk"
+	}
+	return "invalid"
+}
+
+// PacketPriority returns the class of packet p.
+func PacketPriority(p interface{}) Priority {
+	switch p.(type) {
+	case proto.KeepAlive, *proto.KeepAlive:
+		return PriorityKeepAlive
+	case proto.Player, *proto.Player,
+		proto.PlayerPosition, *proto.PlayerPosition,
+		proto.PlayerLook, *proto.PlayerLook,
+		proto.ClientPlayerPositionAndLook, *proto.ClientPlayerPositionAndLook,
+		proto.SteerVehicle, *proto.SteerVehicle,
+		proto.EntityAction, *proto.EntityAction:
+		return PriorityMovement
+	case proto.ClientChatMessage, *proto.ClientChatMessage:
+		return PriorityChat
+	}
+	return PriorityBulk
+}
+
+var (
+	ErrQueueFull   = errors.New("Send queue full")
+	ErrQueueClosed = errors.New("Send queue closed")
+)
+
+// SendQueue sends packets through a Conn asynchronously. Packets are sent
+// in the order of their Priority, those of the same class in the order
+// they were queued. Each class has a separate buffer and rate limit.
+//
+// Packets must not be modified after they are queued.
+type SendQueue struct {
+	c    *Conn
+	wake chan struct{}
+	done chan struct{}
+
+	mtx    sync.Mutex
+	q      [numPriorities][]interface{}
+	size   int
+	limit  [numPriorities]*rateLimit
+	closed bool
+	err    error // error sending packet
+}
+
+// NewSendQueue returns a SendQueue sending packets through c,
+// buffering at most size packets of each class. Chat messages
+// are limited to 5 per 5 seconds by default, to avoid getting
+// kicked for spamming.
+func NewSendQueue(c *Conn, size int) *SendQueue {
+	q := &SendQueue{
+		c:    c,
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+		size: size,
+	}
+	q.SetRateLimit(PriorityChat, 5, 5*time.Second)
+	go q.run()
+	return q
+}
+
+// SetRateLimit limits the packets of class pri to n per duration per,
+// allowing bursts of n packets. The limit is removed if n is zero.
+func (q *SendQueue) SetRateLimit(pri Priority, n int, per time.Duration) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	if n <= 0 {
+		q.limit[pri] = nil
+	} else {
+		q.limit[pri] = &rateLimit{
+			rate:   float64(n) / per.Seconds(),
+			burst:  float64(n),
+			tokens: float64(n),
+			last:   time.Now(),
+		}
+	}
+	q.signal()
+}
+
+// Send queues packet p with its PacketPriority.
+func (q *SendQueue) Send(p interface{}) error {
+	return q.SendPriority(PacketPriority(p), p)
+}
+
+// SendPriority queues packet p in class pri. It returns ErrQueueFull
+// if the buffer of the class is full, ErrQueueClosed after Close, or
+// the error sending an earlier packet.
+func (q *SendQueue) SendPriority(pri Priority, p interface{}) error {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	switch {
+	case q.err != nil:
+		return q.err
+	case q.closed:
+		return ErrQueueClosed
+	case len(q.q[pri]) >= q.size:
+		return ErrQueueFull
+	}
+	q.q[pri] = append(q.q[pri], p)
+	q.signal()
+	return nil
+}
+
+// Len returns the number of packets queued.
+func (q *SendQueue) Len() int {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	n := 0
+	for _, v := range q.q {
+		n += len(v)
+	}
+	return n
+}
+
+// Close stops accepting packets, and waits until the ones
+// already queued are sent. It returns the error sending them, if any.
+func (q *SendQueue) Close() error {
+	q.mtx.Lock()
+	q.closed = true
+	q.signal()
+	q.mtx.Unlock()
+	<-q.done
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	return q.err
+}
+
+// signal wakes up the sender, q.mtx must be held.
+func (q *SendQueue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *SendQueue) run() {
+	defer close(q.done)
+	for {
+		p, wait, ok := q.next()
+		if !ok {
+			return
+		}
+		if p == nil {
+			var tc <-chan time.Time
+			if wait > 0 {
+				tc = time.After(wait)
+			}
+			select {
+			case <-q.wake:
+			case <-tc:
+			}
+			continue
+		}
+		if err := q.c.Send(p); err != nil {
+			q.mtx.Lock()
+			q.err = err
+			q.q = [numPriorities][]interface{}{}
+			q.mtx.Unlock()
+			return
+		}
+	}
+}
+
+// next returns the next packet to send. If no packet may be sent now,
+// it returns the time to wait, or zero to wait until a packet is queued.
+// Ok is false if q is closed and empty.
+func (q *SendQueue) next() (p interface{}, wait time.Duration, ok bool) {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	now := time.Now()
+	empty := true
+	for i, v := range q.q {
+		if len(v) == 0 {
+			continue
+		}
+		empty = false
+		if l := q.limit[i]; l != nil {
+			if w := l.take(now); w > 0 {
+				if wait == 0 || w < wait {
+					wait = w
+				}
+				continue
+			}
+		}
+		p, v[0] = v[0], nil
+		q.q[i] = v[1:]
+		return p, 0, true
+	}
+	return nil, wait, !(empty && q.closed)
+}
+
+// rateLimit is a token bucket.
+type rateLimit struct {
+	rate   float64 // tokens per second
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// take takes a token if one is available and returns zero,
+// otherwise it returns the time until the next token.
+func (l *rateLimit) take(now time.Time) time.Duration {
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.burst {
+		l.tokens = l.burst
+	}
+	l.last = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((1-l.tokens)/l.rate*float64(time.Second)) + 1
+}
@@ -0,0 +1,62 @@
+package net
+
+import (
+	"fmt"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSendQueue(t *testing.T) {
+	c, s := connPair(proto.StatePlay)
+	q := NewSendQueue(c, 3)
+	q.SetRateLimit(PriorityChat, 2, 100*time.Millisecond)
+
+	// blocks until the server starts reading
+	q.Send(proto.ClientHeldItemChange{Slot: 1})
+	for q.Len() != 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for i := 1; i <= 3; i++ {
+		if err := q.Send(proto.ClientChatMessage{Message: fmt.Sprint(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := q.Send(proto.ClientChatMessage{Message: "4"}); err != ErrQueueFull {
+		t.Errorf("got %v, want ErrQueueFull", err)
+	}
+	q.Send(proto.ClientHeldItemChange{Slot: 2})
+	q.Send(&proto.KeepAlive{KeepAliveID: 3})
+
+	gotc := make(chan []string)
+	go func() {
+		var got []string
+		for {
+			p, err := s.Recv()
+			if err != nil {
+				gotc <- got
+				return
+			}
+			switch x := p.(type) {
+			case *proto.ClientChatMessage:
+				got = append(got, "chat"+x.Message)
+			case *proto.ClientHeldItemChange:
+				got = append(got, fmt.Sprint("slot", x.Slot))
+			default:
+				got = append(got, packetName(p))
+			}
+		}
+	}()
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Send(proto.KeepAlive{}); err != ErrQueueClosed {
+		t.Errorf("got %v, want ErrQueueClosed", err)
+	}
+	c.Close()
+	want := []string{"slot1", "KeepAlive", "chat1", "chat2", "slot2", "chat3"}
+	if got := <-gotc; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,138 @@
+package net
+
+import (
+	"bytes"
+	"crypto/aes"
+	crand "crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"io"
+	"net"
+)
+
+// ServerConn is the server side of a connection accepted from a game client.
+type ServerConn struct {
+	Conn
+
+	// Handshake is the packet received by ReadHandshake.
+	Handshake *proto.Handshake
+}
+
+var (
+	ErrVerifyTokenMismatch = errors.New("Verify token mismatch")
+	ErrSharedSecretLength  = errors.New("Invalid shared secret length")
+)
+
+// Accept waits for the next game client to connect to l.
+func Accept(l net.Listener) (*ServerConn, error) {
+	nc, err := l.Accept()
+	if err != nil {
+		return nil, err
+	}
+	c := new(ServerConn)
+	c.init(nc, proto.Server)
+	return c, nil
+}
+
+// ReadHandshake receives the handshake of the client,
+// and switches to the state requested.
+func (c *ServerConn) ReadHandshake() (*proto.Handshake, error) {
+	p, err := c.Recv()
+	if err != nil {
+		return nil, err
+	}
+	h, ok := p.(*proto.Handshake)
+	if !ok {
+		return nil, ErrUnexpectedResponse
+	}
+	switch s := h.StateUpdate(); s {
+	case proto.StateStatus, proto.StateLogin:
+		c.SetState(s)
+	default:
+		return nil, ErrStateInvalid
+	}
+	c.Handshake = h
+	return h, nil
+}
+
+// AcceptLogin receives the login request of the client and returns the
+// name of the player. If key is not nil, it performs the encryption key
+// exchange with key and enables encryption. The client is not verified
+// with the session server, AcceptLogin trusts the player name sent.
+func (c *ServerConn) AcceptLogin(key *rsa.PrivateKey) (name string, err error) {
+	/*
+		C->S : Login Start
+			S->C : Encryption Key Request
+			C->S : Encryption Key Response
+			(Both enable encryption)
+	*/
+	p, err := c.Recv()
+	if err != nil {
+		return "", err
+	}
+	ls, ok := p.(*proto.LoginStart)
+	if !ok {
+		return "", ErrUnexpectedResponse
+	}
+	if key == nil {
+		return ls.Name, nil
+	}
+
+	pubkey, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		return "", err
+	}
+	token := make([]byte, 4)
+	if _, err = io.ReadFull(crand.Reader, token); err != nil {
+		return "", err
+	}
+	err = c.Send(proto.EncryptionRequest{
+		ServerId:    "",
+		PublicKey:   pubkey,
+		VerifyToken: token,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	if p, err = c.Recv(); err != nil {
+		return "", err
+	}
+	ers, ok := p.(*proto.EncryptionResponse)
+	if !ok {
+		return "", ErrUnexpectedResponse
+	}
+	vt, err := rsa.DecryptPKCS1v15(crand.Reader, key, ers.VerifyToken)
+	if err != nil {
+		return "", err
+	}
+	if !bytes.Equal(vt, token) {
+		return "", ErrVerifyTokenMismatch
+	}
+	secret, err := rsa.DecryptPKCS1v15(crand.Reader, key, ers.SharedSecret)
+	if err != nil {
+		return "", err
+	}
+	if len(secret) != aes.BlockSize {
+		return "", ErrSharedSecretLength
+	}
+	c.InitIO(secret)
+	return ls.Name, nil
+}
+
+// LoginSuccess completes the login of the client
+// and switches to play state.
+func (c *ServerConn) LoginSuccess(uuid, name string) error {
+	err := c.Send(proto.LoginSuccess{UUID: uuid, Username: name})
+	if err == nil {
+		c.SetState(proto.StatePlay)
+	}
+	return err
+}
+
+// LoginDisconnect rejects the login of the client with reason.
+func (c *ServerConn) LoginDisconnect(reason string) error {
+	return c.Send(proto.LoginDisconnect{Reason: reason})
+}
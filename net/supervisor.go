@@ -0,0 +1,262 @@
+package net
+
+import (
+	"context"
+	"encoding/json"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Supervisor keeps a client connected to a server. It connects, logs in
+// and runs Handler, and reconnects with jittered exponential backoff when
+// the connection fails or the client is kicked for a reason worth retrying.
+//
+// Auth is started at every login, so YggAuth tokens are validated and
+// refreshed as needed.
+type Supervisor struct {
+	Addr    string
+	Auth    Auth
+	Handler PacketHandler // receives the packets of all sessions
+
+	// Dial, if set, is used instead of Connect.
+	Dial func(addr string) (*ClientConn, error)
+
+	// Setup, if set, is called with each new connection before login,
+	// eg. to set its logger.
+	Setup func(c *ClientConn)
+
+	// OnStart, if set, is called when a session starts after login.
+	OnStart func(c *ClientConn)
+
+	// OnEnd, if set, is called when a session ends with err.
+	// Retry is the delay before reconnecting, or negative
+	// if the Supervisor gives up.
+	OnEnd func(c *ClientConn, err error, retry time.Duration)
+
+	// Retry, if set, reports if connecting again after err is
+	// worthwhile. DefaultRetry is used if nil.
+	Retry func(err error) bool
+
+	// MinBackoff and MaxBackoff limit the delay between attempts,
+	// they default to one second and five minutes. The delay is reset
+	// to MinBackoff after a session lasting at least MaxBackoff.
+	MinBackoff, MaxBackoff time.Duration
+
+	Logger Logger
+
+	mtx sync.Mutex
+	c   *ClientConn // current connection
+}
+
+// Conn returns the current connection, or nil
+// if the supervisor is not connected.
+func (s *Supervisor) Conn() *ClientConn {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.c
+}
+
+// Run connects and runs sessions until ctx is done or an error not worth
+// retrying occurs, and returns the error ending the last session.
+func (s *Supervisor) Run(ctx context.Context) error {
+	minb, maxb := s.MinBackoff, s.MaxBackoff
+	if minb <= 0 {
+		minb = time.Second
+	}
+	if maxb < minb {
+		maxb = 5 * time.Minute
+		if maxb < minb {
+			maxb = minb
+		}
+	}
+	retry := s.Retry
+	if retry == nil {
+		retry = DefaultRetry
+	}
+	backoff := minb
+	for {
+		c, t0, err := s.session(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if !t0.IsZero() && time.Since(t0) >= maxb {
+			backoff = minb
+		}
+		delay := time.Duration(-1)
+		if retry(err) {
+			// between half and full backoff
+			delay = backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+			if backoff *= 2; backoff > maxb {
+				backoff = maxb
+			}
+		}
+		s.log(LevelWarn, "Session ended", "err", err, "retry", delay)
+		if s.OnEnd != nil {
+			s.OnEnd(c, err, delay)
+		}
+		if delay < 0 {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// session runs a single session, and returns its connection
+// and the time of login if it was successful.
+func (s *Supervisor) session(ctx context.Context) (c *ClientConn, t0 time.Time, err error) {
+	dial := s.Dial
+	if dial == nil {
+		dial = Connect
+	}
+	s.log(LevelInfo, "Connecting", "addr", s.Addr)
+	if c, err = dial(s.Addr); err != nil {
+		return
+	}
+	defer c.Close()
+	s.mtx.Lock()
+	s.c = c
+	s.mtx.Unlock()
+	defer func() {
+		s.mtx.Lock()
+		s.c = nil
+		s.mtx.Unlock()
+	}()
+
+	if s.Setup != nil {
+		s.Setup(c)
+	}
+	// abort login if ctx is done
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.Close()
+		case <-done:
+		}
+	}()
+	err = c.Login(s.Auth)
+	close(done)
+	if err != nil {
+		return
+	}
+	t0 = time.Now()
+	if s.OnStart != nil {
+		s.OnStart(c)
+	}
+	err = c.RunContext(ctx, s.Handler)
+	return
+}
+
+func (s *Supervisor) log(level Level, msg string, fields ...interface{}) {
+	if s.Logger != nil {
+		s.Logger.Log(level, msg, fields...)
+	}
+}
+
+// DefaultRetry reports if connecting again after err is worthwhile.
+// It returns false for kicks classified as KickBanned or KickWhitelist,
+// and for authentication errors.
+func DefaultRetry(err error) bool {
+	switch e := err.(type) {
+	case *DisconnectError:
+		switch e.Kick() {
+		case KickBanned, KickWhitelist:
+			return false
+		}
+	case YggError:
+		return false
+	}
+	return true
+}
+
+// KickReason is the kind of reason of a server closing a connection.
+type KickReason int
+
+const (
+	KickUnknown KickReason = iota
+	KickBanned
+	KickWhitelist
+	KickServerFull
+	KickRestarting
+)
+
+func (k KickReason) String() string {
+	switch k {
+	case KickBanned:
+		return "banned"
+	case KickWhitelist:
+		return "whitelist"
+	case KickServerFull:
+		return "server full"
+	case KickRestarting:
+		return "restarting"
+	}
+	return "unknown"
+}
+
+// kickPatterns are the text fragments of reasons sent by
+// vanilla and common servers, lower case.
+var kickPatterns = []struct {
+	k KickReason
+	s []string
+}{
+	{KickBanned, []string{"banned"}},
+	{KickWhitelist, []string{"white-list", "whitelist"}},
+	{KickServerFull, []string{"server is full", "server full", "server_full"}},
+	{KickRestarting, []string{"restart", "server closed", "shutting down", "shutdown"}},
+}
+
+// Kick classifies the reason of e.
+func (e *DisconnectError) Kick() KickReason {
+	return ClassifyKick(e.Reason)
+}
+
+// ClassifyKick returns the kind of reason, a JSON chat message
+// or plain text sent by the server when closing a connection.
+func ClassifyKick(reason string) KickReason {
+	t := strings.ToLower(ChatText(reason))
+	for _, p := range kickPatterns {
+		for _, s := range p.s {
+			if strings.Contains(t, s) {
+				return p.k
+			}
+		}
+	}
+	return KickUnknown
+}
+
+// ChatText returns the text of the JSON chat message m, including
+// translation keys and their arguments. If m is not valid JSON,
+// it is returned unchanged.
+func ChatText(m string) string {
+	var v interface{}
+	if json.Unmarshal([]byte(m), &v) != nil {
+		return m
+	}
+	var parts []string
+	var walk func(v interface{})
+	walk = func(v interface{}) {
+		switch x := v.(type) {
+		case string:
+			parts = append(parts, x)
+		case []interface{}:
+			for _, e := range x {
+				walk(e)
+			}
+		case map[string]interface{}:
+			for _, k := range []string{"translate", "text", "with", "extra"} {
+				if e, ok := x[k]; ok {
+					walk(e)
+				}
+			}
+		}
+	}
+	walk(v)
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,73 @@
+package net
+
+import (
+	"context"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestClassifyKick(t *testing.T) {
+	tests := []struct {
+		reason string
+		want   KickReason
+	}{
+		{`{"text":"You are banned from this server!"}`, KickBanned},
+		{`You are not white-listed on this server!`, KickWhitelist},
+		{`{"translate":"multiplayer.disconnect.server_full"}`, KickServerFull},
+		{`{"text":"","extra":[{"text":"Server "},{"text":"restarting","color":"red"}]}`, KickRestarting},
+		{`"Server closed"`, KickRestarting},
+		{`{"text":"Flying is not enabled on this server"}`, KickUnknown},
+	}
+	for _, tt := range tests {
+		if got := ClassifyKick(tt.reason); got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.reason, got, tt.want)
+		}
+	}
+}
+
+func TestSupervisor(t *testing.T) {
+	kicks := []string{`{"text":"Server closed"}`, `{"text":"You are banned"}`}
+	nsess := 0
+	var ends []time.Duration
+	s := &Supervisor{
+		Addr:    "localhost",
+		Auth:    testAuth("Steve"),
+		Handler: nopHandler{},
+		Dial: func(addr string) (*ClientConn, error) {
+			cc, sc := net.Pipe()
+			reason := kicks[0]
+			kicks = kicks[1:]
+			go func() {
+				c := &ServerConn{}
+				c.init(sc, proto.Server)
+				defer c.Close()
+				if _, err := c.ReadHandshake(); err != nil {
+					return
+				}
+				name, err := c.AcceptLogin(nil)
+				if err != nil {
+					return
+				}
+				c.LoginSuccess("uuid-"+name, name)
+				c.Disconnect(reason)
+			}()
+			return newTestClientConn(cc), nil
+		},
+		OnStart: func(c *ClientConn) {
+			nsess++
+		},
+		OnEnd: func(c *ClientConn, err error, retry time.Duration) {
+			ends = append(ends, retry)
+		},
+		MinBackoff: time.Millisecond,
+	}
+	err := s.Run(context.Background())
+	if de, ok := err.(*DisconnectError); !ok || de.Kick() != KickBanned {
+		t.Errorf("got %v, want banned", err)
+	}
+	if nsess != 2 || len(ends) != 2 || ends[0] < 0 || ends[1] >= 0 {
+		t.Errorf("%d sessions, retries %v", nsess, ends)
+	}
+}
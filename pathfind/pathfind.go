@@ -0,0 +1,270 @@
+// Package pathfind finds routes players can walk along in a world.
+package pathfind
+
+import (
+	"container/heap"
+	"errors"
+	"github.com/tajtiattila/mctoy/blocks"
+	"github.com/tajtiattila/mctoy/physics"
+	"math"
+)
+
+// Pos is the position of a block. In paths it is the block
+// the feet of the player are in.
+type Pos struct {
+	X, Y, Z int
+}
+
+// Add returns the sum of p and q.
+func (p Pos) Add(q Pos) Pos {
+	return Pos{p.X + q.X, p.Y + q.Y, p.Z + q.Z}
+}
+
+// Dist returns the distance between p and q.
+func (p Pos) Dist(q Pos) float64 {
+	dx, dy, dz := float64(p.X-q.X), float64(p.Y-q.Y), float64(p.Z-q.Z)
+	return math.Sqrt(dx*dx + dy*dy + dz*dz)
+}
+
+var (
+	ErrNoPath = errors.New("pathfind: no path found")
+	ErrBudget = errors.New("pathfind: node budget exhausted")
+)
+
+// Costs are the costs of moving one block in the various ways.
+type Costs struct {
+	Walk  float64 // to a neighbouring block on the same level
+	Jump  float64 // up a block
+	Drop  float64 // for each block fallen
+	Swim  float64 // through water
+	Climb float64 // up or down a ladder or vine
+}
+
+// DefaultCosts prefers walking and avoids long falls.
+var DefaultCosts = Costs{
+	Walk:  1,
+	Jump:  2,
+	Drop:  1.5,
+	Swim:  3,
+	Climb: 1.5,
+}
+
+// Finder finds paths with the A* algorithm. The zero value of its
+// fields other than World select the defaults.
+type Finder struct {
+	World physics.BlockSource
+
+	// Costs of moves, DefaultCosts if zero.
+	Costs Costs
+
+	// MaxDrop is the highest fall in blocks considered safe, 3 if zero.
+	// Falls into water may be higher.
+	MaxDrop int
+
+	// MaxNodes is the number of nodes visited before giving up,
+	// 10000 if zero.
+	MaxNodes int
+}
+
+type node struct {
+	p     Pos
+	g, f  float64 // cost from start, estimated total cost
+	prev  *node
+	index int // in heap, -1 when visited
+}
+
+// Find returns the path from the block from to the block to, including
+// both. If the node budget is exhausted, it returns the path to the
+// node closest to the goal together with ErrBudget.
+func (f *Finder) Find(from, to Pos) ([]Pos, error) {
+	costs, maxNodes := f.Costs, f.MaxNodes
+	if costs == (Costs{}) {
+		costs = DefaultCosts
+	}
+	if maxNodes <= 0 {
+		maxNodes = 10000
+	}
+	minCost := math.Min(costs.Walk, math.Min(costs.Swim, costs.Climb))
+
+	start := &node{p: from, f: from.Dist(to) * minCost}
+	nodes := map[Pos]*node{from: start}
+	open := nodeHeap{start}
+	best := start
+	for visited := 0; len(open) != 0; visited++ {
+		n := heap.Pop(&open).(*node)
+		if n.p == to {
+			return n.path(), nil
+		}
+		if n.f-n.g < best.f-best.g {
+			best = n
+		}
+		if visited >= maxNodes {
+			return best.path(), ErrBudget
+		}
+		f.neighbours(n.p, &costs, func(p Pos, cost float64) {
+			g := n.g + cost
+			m := nodes[p]
+			switch {
+			case m == nil:
+				m = &node{p: p, g: g, f: g + p.Dist(to)*minCost, prev: n}
+				nodes[p] = m
+				heap.Push(&open, m)
+			case g < m.g && m.index >= 0:
+				m.f += g - m.g
+				m.g, m.prev = g, n
+				heap.Fix(&open, m.index)
+			}
+		})
+	}
+	return nil, ErrNoPath
+}
+
+func (n *node) path() []Pos {
+	var v []Pos
+	for ; n != nil; n = n.prev {
+		v = append(v, n.p)
+	}
+	for i, j := 0, len(v)-1; i < j; i, j = i+1, j-1 {
+		v[i], v[j] = v[j], v[i]
+	}
+	return v
+}
+
+var (
+	up    = Pos{0, 1, 0}
+	down  = Pos{0, -1, 0}
+	sides = []Pos{{1, 0, 0}, {-1, 0, 0}, {0, 0, 1}, {0, 0, -1}}
+)
+
+// neighbours calls fn with the positions reachable from p in one move.
+func (f *Finder) neighbours(p Pos, c *Costs, fn func(p Pos, cost float64)) {
+	water, ladder := f.water(p), f.climbable(p)
+	supported := f.floor(p) || water || ladder
+	for _, d := range sides {
+		q := p.Add(d)
+		switch {
+		case f.standable(q):
+			if f.water(q) {
+				fn(q, c.Swim)
+			} else {
+				fn(q, c.Walk)
+			}
+		case f.body(q):
+			// walk off the edge
+			if y, ok := f.landing(q); ok {
+				fn(Pos{q.X, y, q.Z}, c.Walk+c.Drop*float64(q.Y-y))
+			}
+		case supported && f.clear(p.Add(up).Add(up)) && f.standable(q.Add(up)):
+			fn(q.Add(up), c.Jump)
+		}
+	}
+	// diagonals on the same level, with both sides clear
+	for i := 0; i < 2; i++ {
+		for j := 2; j < 4; j++ {
+			q := p.Add(sides[i]).Add(sides[j])
+			if f.body(p.Add(sides[i])) && f.body(p.Add(sides[j])) && f.standable(q) && !f.water(q) {
+				fn(q, c.Walk*math.Sqrt2)
+			}
+		}
+	}
+	if q := p.Add(up); (ladder || water) && f.standable(q) {
+		if water {
+			fn(q, c.Swim)
+		} else {
+			fn(q, c.Climb)
+		}
+	}
+	if q := p.Add(down); f.standable(q) && (f.climbable(q) || f.water(q)) {
+		if f.water(q) {
+			fn(q, c.Swim)
+		} else {
+			fn(q, c.Climb)
+		}
+	}
+}
+
+// landing returns the height a player falls to from p.
+func (f *Finder) landing(p Pos) (int, bool) {
+	maxDrop := f.MaxDrop
+	if maxDrop <= 0 {
+		maxDrop = 3
+	}
+	for y := p.Y - 1; y >= 0; y-- {
+		q := Pos{p.X, y, p.Z}
+		if !f.clear(q) {
+			return 0, false
+		}
+		if f.water(q) {
+			return y, true
+		}
+		if f.floor(q) || f.climbable(q) {
+			return y, p.Y-y <= maxDrop
+		}
+	}
+	return 0, false
+}
+
+func (f *Finder) block(p Pos) (id, meta int) {
+	return f.World.Block(p.X, p.Y, p.Z)
+}
+
+// clear reports if a player may be in block p.
+func (f *Finder) clear(p Pos) bool {
+	id, meta := f.block(p)
+	if blocks.IsClimbable(id) {
+		return true
+	}
+	return blocks.Passable(id, meta) && !blocks.IsLava(id) && id != blocks.Fire && id != blocks.Web
+}
+
+// body reports if there is room for a player with feet in block p.
+func (f *Finder) body(p Pos) bool {
+	return f.clear(p) && f.clear(p.Add(up))
+}
+
+// floor reports if the block below p can be stood upon.
+func (f *Finder) floor(p Pos) bool {
+	id, meta := f.block(p.Add(down))
+	return !blocks.Passable(id, meta) && blocks.Height(id, meta) <= 1 && id != blocks.Cactus
+}
+
+func (f *Finder) water(p Pos) bool {
+	id, _ := f.block(p)
+	return blocks.IsWater(id)
+}
+
+func (f *Finder) climbable(p Pos) bool {
+	id, _ := f.block(p)
+	return blocks.IsClimbable(id)
+}
+
+// standable reports if a player may stay with feet in block p.
+func (f *Finder) standable(p Pos) bool {
+	return f.body(p) && (f.floor(p) || f.water(p) || f.climbable(p))
+}
+
+type nodeHeap []*node
+
+func (h nodeHeap) Len() int { return len(h) }
+
+func (h nodeHeap) Less(i, j int) bool { return h[i].f < h[j].f }
+
+func (h nodeHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].index, h[j].index = i, j
+}
+
+func (h *nodeHeap) Push(x interface{}) {
+	n := x.(*node)
+	n.index = len(*h)
+	*h = append(*h, n)
+}
+
+func (h *nodeHeap) Pop() interface{} {
+	old := *h
+	n := old[len(old)-1]
+	old[len(old)-1] = nil
+	*h = old[:len(old)-1]
+	n.index = -1
+	return n
+}
@@ -0,0 +1,141 @@
+package pathfind
+
+import (
+	"testing"
+)
+
+type testWorld map[Pos][2]int
+
+func (w testWorld) Block(x, y, z int) (id, meta int) {
+	b := w[Pos{x, y, z}]
+	return b[0], b[1]
+}
+
+func (w testWorld) fill(x0, y0, z0, x1, y1, z1, id, meta int) {
+	for x := x0; x <= x1; x++ {
+		for y := y0; y <= y1; y++ {
+			for z := z0; z <= z1; z++ {
+				w[Pos{x, y, z}] = [2]int{id, meta}
+			}
+		}
+	}
+}
+
+// floorWorld returns a stone floor at y = 63 from 0, 0 to 15, 15.
+func floorWorld() testWorld {
+	w := make(testWorld)
+	w.fill(0, 63, 0, 15, 63, 15, 1, 0)
+	return w
+}
+
+// checkPath checks that consecutive steps of v are neighbours.
+func checkPath(t *testing.T, v []Pos, from, to Pos) {
+	if len(v) == 0 || v[0] != from || v[len(v)-1] != to {
+		t.Fatalf("path %v, want from %v to %v", v, from, to)
+	}
+	for i := 1; i < len(v); i++ {
+		d := v[i].Add(Pos{-v[i-1].X, -v[i-1].Y, -v[i-1].Z})
+		if d.X*d.X > 1 || d.Z*d.Z > 1 {
+			t.Fatalf("path %v: step %v too long", v, d)
+		}
+	}
+}
+
+func TestFlat(t *testing.T) {
+	f := &Finder{World: floorWorld()}
+	from, to := Pos{1, 64, 1}, Pos{10, 64, 5}
+	v, err := f.Find(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPath(t, v, from, to)
+	if len(v) != 10 {
+		t.Errorf("path %v has %d nodes, want 10", v, len(v))
+	}
+}
+
+func TestWallAndJump(t *testing.T) {
+	w := floorWorld()
+	// wall across the floor with a single block step at z = 15
+	w.fill(5, 64, 0, 5, 66, 14, 1, 0)
+	w.fill(5, 64, 15, 5, 64, 15, 1, 0)
+	f := &Finder{World: w}
+	from, to := Pos{1, 64, 1}, Pos{10, 64, 1}
+	v, err := f.Find(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPath(t, v, from, to)
+	found := false
+	for _, p := range v {
+		if p == (Pos{5, 65, 15}) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("path %v does not go over the step", v)
+	}
+
+	// make the step two blocks high
+	w.fill(5, 65, 15, 5, 65, 15, 1, 0)
+	if _, err := f.Find(from, to); err != ErrNoPath {
+		t.Errorf("got %v, want ErrNoPath", err)
+	}
+}
+
+func TestDrop(t *testing.T) {
+	w := make(testWorld)
+	w.fill(0, 70, 0, 3, 70, 0, 1, 0)
+	w.fill(4, 67, 0, 8, 67, 0, 1, 0)
+	f := &Finder{World: w}
+	from, to := Pos{0, 71, 0}, Pos{8, 68, 0}
+	v, err := f.Find(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPath(t, v, from, to)
+
+	f.MaxDrop = 2
+	if _, err := f.Find(from, to); err != ErrNoPath {
+		t.Errorf("got %v, want ErrNoPath", err)
+	}
+	if _, err := f.Find(to, from); err != ErrNoPath {
+		t.Errorf("climbed up, got %v", err)
+	}
+}
+
+func TestLadderAndWater(t *testing.T) {
+	w := floorWorld()
+	w.fill(0, 64, 5, 15, 70, 5, 1, 0)
+	w.fill(3, 64, 4, 3, 70, 4, 65, 2)
+	w.fill(0, 71, 5, 15, 71, 5, 0, 0)
+	f := &Finder{World: w}
+	from, to := Pos{3, 64, 1}, Pos{3, 71, 5}
+	v, err := f.Find(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPath(t, v, from, to)
+
+	w = floorWorld()
+	w.fill(0, 60, 5, 15, 63, 8, 9, 0)
+	f = &Finder{World: w}
+	from, to = Pos{1, 64, 1}, Pos{1, 64, 12}
+	v, err = f.Find(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkPath(t, v, from, to)
+}
+
+func TestBudget(t *testing.T) {
+	f := &Finder{World: floorWorld(), MaxNodes: 10}
+	from, to := Pos{1, 64, 1}, Pos{14, 64, 14}
+	v, err := f.Find(from, to)
+	if err != ErrBudget {
+		t.Fatalf("got %v, want ErrBudget", err)
+	}
+	if len(v) < 2 || v[0] != from || v[len(v)-1].Dist(to) >= from.Dist(to) {
+		t.Errorf("partial path %v does not approach goal", v)
+	}
+}
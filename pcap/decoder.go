@@ -0,0 +1,243 @@
+package pcap
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	mcnet "github.com/tajtiattila/mctoy/net"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"io"
+)
+
+// Decoder decodes the packets of Minecraft connections in a capture file.
+//
+// TCP streams on the server port are reassembled, and split into packets
+// that are decoded according to the state of the connection. Connections
+// captured without their handshake are assumed to be in play state.
+type Decoder struct {
+	// Port is the server port of Minecraft connections.
+	Port int
+
+	// Secret, if set, returns the AES shared secret of the connection
+	// between client and server, or nil if it is unknown.
+	// Encrypted connections are decoded only if their secret is known.
+	Secret func(client, server Endpoint) []byte
+
+	pr    *Reader
+	conns map[[2]Endpoint]*Stream
+	nid   int
+	queue []*Record
+}
+
+// Stream is a Minecraft connection in a capture file.
+type Stream struct {
+	ID      int // sequence number of the connection in the file
+	Client  Endpoint
+	Server  Endpoint
+	State   proto.CxnState
+	Version int // protocol version from the handshake
+
+	half [2]*halfDecoder // indexed by mcnet.Direction
+}
+
+// Record is a packet decoded from a capture file. If Err is set,
+// the packet could not be decoded, and Payload holds the raw packet
+// if it is available.
+type Record struct {
+	Stream *Stream
+	mcnet.CaptureRecord
+	Packet interface{}
+	Err    error
+}
+
+var (
+	ErrEncrypted = errors.New("Connection encrypted, secret unknown")
+	ErrFrame     = errors.New("Packet length invalid")
+	ErrSecret    = errors.New("Shared secret length invalid")
+)
+
+// NewDecoder returns a Decoder for the capture file in r.
+func NewDecoder(r io.Reader) (*Decoder, error) {
+	pr, err := NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	return &Decoder{
+		Port:  25565,
+		pr:    pr,
+		conns: make(map[[2]Endpoint]*Stream),
+	}, nil
+}
+
+// Next returns the next packet decoded, or io.EOF at the end of the file.
+func (d *Decoder) Next() (*Record, error) {
+	for len(d.queue) == 0 {
+		f, err := d.pr.Next()
+		if err != nil {
+			return nil, err
+		}
+		s, err := DecodeSegment(d.pr.LinkType, f.Data)
+		if err == ErrLinkType {
+			return nil, err
+		}
+		if err != nil {
+			continue
+		}
+		d.segment(f, s)
+	}
+	r := d.queue[0]
+	d.queue = d.queue[1:]
+	return r, nil
+}
+
+func (d *Decoder) segment(f *Frame, s *Segment) {
+	var (
+		key [2]Endpoint
+		dir mcnet.Direction
+	)
+	switch d.Port {
+	case s.Dst.Port:
+		key, dir = [2]Endpoint{s.Src, s.Dst}, mcnet.Serverbound
+	case s.Src.Port:
+		key, dir = [2]Endpoint{s.Dst, s.Src}, mcnet.Clientbound
+	default:
+		return
+	}
+	st := d.conns[key]
+	if st == nil {
+		if s.RST || (!s.SYN && len(s.Payload) == 0) {
+			return
+		}
+		d.nid++
+		st = &Stream{
+			ID:      d.nid,
+			Client:  key[0],
+			Server:  key[1],
+			State:   proto.StateHandshake,
+			Version: proto.ProtocolVersion,
+		}
+		if !s.SYN {
+			st.State = proto.StatePlay
+		}
+		st.half[0], st.half[1] = new(halfDecoder), new(halfDecoder)
+		d.conns[key] = st
+	}
+	h := st.half[dir]
+	if !h.done {
+		b, err := h.add(s)
+		if err != nil {
+			d.fail(f, st, dir, err)
+		} else if len(b) != 0 {
+			h.write(b)
+			d.decode(f, st, dir)
+		}
+	}
+	if s.RST || (st.half[0].closed && st.half[1].closed) {
+		delete(d.conns, key)
+	} else if s.FIN {
+		h.closed = true
+	}
+}
+
+// halfDecoder decodes packets from one direction of a connection.
+type halfDecoder struct {
+	halfStream
+	buf    []byte
+	dec    cipher.Stream // decrypts data if not nil
+	done   bool          // stop decoding after an error
+	closed bool
+}
+
+func (h *halfDecoder) write(b []byte) {
+	if h.dec != nil {
+		h.dec.XORKeyStream(b, b)
+	}
+	h.buf = append(h.buf, b...)
+}
+
+func (d *Decoder) fail(f *Frame, st *Stream, dir mcnet.Direction, err error) {
+	st.half[dir].done = true
+	d.emit(f, st, dir, nil, nil, err)
+}
+
+func (d *Decoder) emit(f *Frame, st *Stream, dir mcnet.Direction, payload []byte, p interface{}, err error) {
+	d.queue = append(d.queue, &Record{
+		Stream: st,
+		CaptureRecord: mcnet.CaptureRecord{
+			Time:    f.Time,
+			Dir:     dir,
+			State:   st.State,
+			Version: st.Version,
+			Payload: payload,
+		},
+		Packet: p,
+		Err:    err,
+	})
+}
+
+func (d *Decoder) decode(f *Frame, st *Stream, dir mcnet.Direction) {
+	h := st.half[dir]
+	pos := 0
+	for !h.done {
+		l, n := binary.Uvarint(h.buf[pos:])
+		if n == 0 {
+			break
+		}
+		if n < 0 || l > maxFrameLen {
+			d.fail(f, st, dir, ErrFrame)
+			break
+		}
+		if len(h.buf)-pos-n < int(l) {
+			break
+		}
+		payload := append([]byte(nil), h.buf[pos+n:pos+n+int(l)]...)
+		pos += n + int(l)
+
+		var (
+			p   interface{}
+			err error
+		)
+		if hs := proto.GetHostState(dir.Receiver(), st.State); hs != nil {
+			p, err = hs.Decode(payload)
+		} else {
+			err = mcnet.ErrStateInvalid
+		}
+		d.emit(f, st, dir, payload, p, err)
+
+		switch x := p.(type) {
+		case *proto.Handshake:
+			st.Version = int(x.ProtocolVersion)
+			st.State = x.StateUpdate()
+		case *proto.LoginSuccess:
+			st.State = x.StateUpdate()
+		case *proto.EncryptionRequest, *proto.EncryptionResponse:
+			// data following these packets is encrypted
+			if err = d.encrypted(st, h, h.buf[pos:]); err != nil {
+				d.fail(f, st, dir, err)
+			}
+		}
+	}
+	h.buf = append(h.buf[:0], h.buf[pos:]...)
+}
+
+// encrypted sets up decryption of h, and decrypts rest in place.
+func (d *Decoder) encrypted(st *Stream, h *halfDecoder, rest []byte) error {
+	var secret []byte
+	if d.Secret != nil {
+		secret = d.Secret(st.Client, st.Server)
+	}
+	if secret == nil {
+		return ErrEncrypted
+	}
+	if len(secret) != aes.BlockSize {
+		return ErrSecret
+	}
+	aesc, err := aes.NewCipher(secret)
+	if err != nil {
+		return err
+	}
+	h.dec = mcnet.NewCFB8Decrypter(aesc, secret)
+	h.dec.XORKeyStream(rest, rest)
+	return nil
+}
@@ -0,0 +1,207 @@
+package pcap
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	mcnet "github.com/tajtiattila/mctoy/net"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// pcapWriter writes Ethernet frames of a single TCP connection.
+type pcapWriter struct {
+	bytes.Buffer
+	t   time.Time
+	seq [2]uint32 // next sequence number by direction
+}
+
+var (
+	testClient = Endpoint{"\x0a\x00\x00\x02", 50000}
+	testServer = Endpoint{"\x0a\x00\x00\x01", 25565}
+)
+
+func newPcapWriter() *pcapWriter {
+	w := &pcapWriter{t: time.Unix(1400000000, 0), seq: [2]uint32{1000, 0xfffffff0}}
+	h := make([]byte, 24)
+	binary.LittleEndian.PutUint32(h[0:], 0xa1b2c3d4)
+	binary.LittleEndian.PutUint16(h[4:], 2)
+	binary.LittleEndian.PutUint16(h[6:], 4)
+	binary.LittleEndian.PutUint32(h[16:], 65535)
+	binary.LittleEndian.PutUint32(h[20:], uint32(LinkEthernet))
+	w.Write(h)
+	return w
+}
+
+// segment writes a segment in direction d at sequence number seq.
+func (w *pcapWriter) segment(d mcnet.Direction, seq uint32, flags byte, payload []byte) {
+	src, dst := testClient, testServer
+	if d == mcnet.Clientbound {
+		src, dst = dst, src
+	}
+	tcp := make([]byte, 20, 20+len(payload))
+	binary.BigEndian.PutUint16(tcp[0:], uint16(src.Port))
+	binary.BigEndian.PutUint16(tcp[2:], uint16(dst.Port))
+	binary.BigEndian.PutUint32(tcp[4:], seq)
+	tcp[12], tcp[13] = 5<<4, flags|0x10
+	tcp = append(tcp, payload...)
+
+	ip := make([]byte, 20, 20+len(tcp))
+	ip[0] = 0x45
+	binary.BigEndian.PutUint16(ip[2:], uint16(20+len(tcp)))
+	ip[8], ip[9] = 64, 6
+	copy(ip[12:], src.IP)
+	copy(ip[16:], dst.IP)
+	ip = append(ip, tcp...)
+
+	eth := make([]byte, 14, 14+len(ip))
+	binary.BigEndian.PutUint16(eth[12:], 0x0800)
+	eth = append(eth, ip...)
+
+	w.t = w.t.Add(time.Millisecond)
+	h := make([]byte, 16)
+	binary.LittleEndian.PutUint32(h[0:], uint32(w.t.Unix()))
+	binary.LittleEndian.PutUint32(h[4:], uint32(w.t.Nanosecond()/1000))
+	binary.LittleEndian.PutUint32(h[8:], uint32(len(eth)))
+	binary.LittleEndian.PutUint32(h[12:], uint32(len(eth)))
+	w.Write(h)
+	w.Write(eth)
+}
+
+// send writes data in direction d split into segments of length n.
+func (w *pcapWriter) send(d mcnet.Direction, data []byte, n int) {
+	for len(data) != 0 {
+		m := n
+		if m > len(data) {
+			m = len(data)
+		}
+		w.segment(d, w.seq[d], 0x08, data[:m])
+		w.seq[d] += uint32(m)
+		data = data[m:]
+	}
+}
+
+func (w *pcapWriter) syn(d mcnet.Direction) {
+	w.segment(d, w.seq[d], 0x02, nil)
+	w.seq[d]++
+}
+
+// frame returns the framed encoding of p sent in direction d in state s.
+func frame(t *testing.T, d mcnet.Direction, s proto.CxnState, p interface{}) []byte {
+	buf := make([]byte, 1024)
+	n, err := proto.GetHostState(1-d.Receiver(), s).Encode(buf, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var lb [binary.MaxVarintLen64]byte
+	nl := binary.PutUvarint(lb[:], uint64(n))
+	return append(lb[:nl], buf[:n]...)
+}
+
+func TestDecoder(t *testing.T) {
+	secret := []byte("0123456789abcdef")
+	encrypter := func() cipher.Stream {
+		c, err := aes.NewCipher(secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return mcnet.NewCFB8Encrypter(c, secret)
+	}
+	sb, cb := mcnet.Serverbound, mcnet.Clientbound
+	packets := []struct {
+		d mcnet.Direction
+		s proto.CxnState
+		p interface{}
+	}{
+		{sb, proto.StateHandshake, &proto.Handshake{ProtocolVersion: 4, ServerAddress: "localhost", ServerPort: 25565, NextState: 2}},
+		{sb, proto.StateLogin, &proto.LoginStart{Name: "Steve"}},
+		{cb, proto.StateLogin, &proto.EncryptionRequest{ServerId: "", PublicKey: []byte("key"), VerifyToken: []byte("tokn")}},
+		{sb, proto.StateLogin, &proto.EncryptionResponse{SharedSecret: []byte("secret"), VerifyToken: []byte("tokn")}},
+		{cb, proto.StateLogin, &proto.LoginSuccess{UUID: "uuid", Username: "Steve"}},
+		{cb, proto.StatePlay, &proto.KeepAlive{KeepAliveID: 42}},
+		{sb, proto.StatePlay, &proto.KeepAlive{KeepAliveID: 42}},
+		{sb, proto.StatePlay, &proto.ClientChatMessage{Message: "hello"}},
+	}
+
+	w := newPcapWriter()
+	w.syn(sb)
+	w.syn(cb)
+
+	// handshake and login start, with the second segment
+	// arriving first and the first one retransmitted
+	b := append(frame(t, sb, packets[0].s, packets[0].p), frame(t, sb, packets[1].s, packets[1].p)...)
+	seq := w.seq[sb]
+	w.segment(sb, seq+10, 0x08, b[10:])
+	w.segment(sb, seq, 0x08, b[:10])
+	w.segment(sb, seq, 0x08, b[:12])
+	w.seq[sb] += uint32(len(b))
+
+	w.send(cb, frame(t, cb, packets[2].s, packets[2].p), 1000)
+	w.send(sb, frame(t, sb, packets[3].s, packets[3].p), 1000)
+
+	enc := [2]cipher.Stream{encrypter(), encrypter()}
+	for _, x := range packets[4:] {
+		b := frame(t, x.d, x.s, x.p)
+		enc[x.d].XORKeyStream(b, b)
+		w.send(x.d, b, 3)
+	}
+
+	d, err := NewDecoder(bytes.NewReader(w.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Secret = func(c, s Endpoint) []byte {
+		if c != testClient || s != testServer {
+			t.Errorf("unexpected endpoints %s %s", c, s)
+		}
+		return secret
+	}
+	for i, x := range packets {
+		r, err := d.Next()
+		if err != nil {
+			t.Fatal(i, err)
+		}
+		if r.Err != nil {
+			t.Fatal(i, r.Err)
+		}
+		if r.Dir != x.d || r.State != x.s || !reflect.DeepEqual(r.Packet, x.p) {
+			t.Errorf("%d: got %s %s %+v, want %s %s %+v", i,
+				r.Dir, proto.CxnStateString(r.State), r.Packet,
+				x.d, proto.CxnStateString(x.s), x.p)
+		}
+		if r.Stream.ID != 1 {
+			t.Errorf("%d: stream id %d", i, r.Stream.ID)
+		}
+	}
+	if r, err := d.Next(); err != io.EOF {
+		t.Errorf("expected EOF, got %+v %v", r, err)
+	}
+
+	// without secret, decoding must stop at encryption
+	d, err = NewDecoder(bytes.NewReader(w.Bytes()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var nerr int
+	for {
+		r, err := d.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Err == ErrEncrypted {
+			nerr++
+		} else if r.Err != nil {
+			t.Error(r.Err)
+		}
+	}
+	if nerr != 2 {
+		t.Errorf("got %d encrypted errors, want 2", nerr)
+	}
+}
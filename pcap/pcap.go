@@ -0,0 +1,104 @@
+// Package pcap decodes Minecraft connections from libpcap capture files.
+package pcap
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"io"
+	"time"
+)
+
+// LinkType is the link layer header type of frames in a capture file.
+type LinkType uint32
+
+const (
+	LinkNull     LinkType = 0   // BSD loopback
+	LinkEthernet LinkType = 1   // Ethernet II
+	LinkRaw      LinkType = 101 // raw IPv4 or IPv6
+	LinkLoop     LinkType = 108 // OpenBSD loopback
+	LinkSLL      LinkType = 113 // Linux cooked capture
+	LinkSLL2     LinkType = 276 // Linux cooked capture v2
+)
+
+var (
+	ErrPcapInvalid  = errors.New("Not a pcap file")
+	ErrPcapNG       = errors.New("pcapng files are unsupported, convert with: editcap -F pcap")
+	ErrFrameTooLong = errors.New("pcap frame too long")
+)
+
+// Frame is a single link layer frame captured.
+type Frame struct {
+	Time time.Time
+	Data []byte // captured bytes, may be truncated to the snapshot length
+}
+
+// Reader reads frames from a libpcap capture file.
+type Reader struct {
+	LinkType LinkType
+
+	r     *bufio.Reader
+	order binary.ByteOrder
+	nano  bool // timestamps have nanosecond resolution
+	hdr   [16]byte
+	buf   []byte
+}
+
+// maxFrameLen is the maximum length of frames accepted.
+const maxFrameLen = 1 << 18
+
+// NewReader reads the file header from r, and returns a Reader for the
+// frames in the file.
+func NewReader(r io.Reader) (*Reader, error) {
+	pr := &Reader{r: bufio.NewReader(r)}
+	var h [24]byte
+	if _, err := io.ReadFull(pr.r, h[:]); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			err = ErrPcapInvalid
+		}
+		return nil, err
+	}
+	switch m := binary.LittleEndian.Uint32(h[:4]); m {
+	case 0xa1b2c3d4, 0xa1b23c4d:
+		pr.order, pr.nano = binary.LittleEndian, m == 0xa1b23c4d
+	case 0xd4c3b2a1, 0x4d3cb2a1:
+		pr.order, pr.nano = binary.BigEndian, m == 0x4d3cb2a1
+	case 0x0a0d0d0a:
+		return nil, ErrPcapNG
+	default:
+		return nil, ErrPcapInvalid
+	}
+	pr.LinkType = LinkType(pr.order.Uint32(h[20:]) & 0xffff)
+	return pr, nil
+}
+
+// Next returns the next frame in the file, or io.EOF at the end.
+// The frame data is valid until the next call to Next.
+func (pr *Reader) Next() (*Frame, error) {
+	if _, err := io.ReadFull(pr.r, pr.hdr[:]); err != nil {
+		if err == io.ErrUnexpectedEOF {
+			err = ErrPcapInvalid
+		}
+		return nil, err
+	}
+	sec := int64(pr.order.Uint32(pr.hdr[0:]))
+	frac := int64(pr.order.Uint32(pr.hdr[4:]))
+	n := int(pr.order.Uint32(pr.hdr[8:]))
+	if n > maxFrameLen {
+		return nil, ErrFrameTooLong
+	}
+	if !pr.nano {
+		frac *= 1000
+	}
+	if cap(pr.buf) < n {
+		pr.buf = make([]byte, n)
+	}
+	b := pr.buf[:n]
+	if _, err := io.ReadFull(pr.r, b); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			err = ErrPcapInvalid
+		}
+		return nil, err
+	}
+	return &Frame{Time: time.Unix(sec, frac), Data: b}, nil
+}
@@ -0,0 +1,215 @@
+package pcap
+
+import (
+	"encoding/binary"
+	"errors"
+	"net"
+	"strconv"
+)
+
+// Segment is a TCP segment.
+type Segment struct {
+	Src, Dst Endpoint
+	Seq      uint32
+	SYN      bool
+	FIN      bool
+	RST      bool
+	Payload  []byte
+}
+
+// Endpoint is one end of a TCP connection.
+type Endpoint struct {
+	IP   string // IP address in binary form, 4 or 16 bytes
+	Port int
+}
+
+func (e Endpoint) String() string {
+	return net.JoinHostPort(net.IP(e.IP).String(), strconv.Itoa(e.Port))
+}
+
+var (
+	ErrNotTCP    = errors.New("Frame is not a TCP segment")
+	ErrTruncated = errors.New("Frame truncated")
+	ErrLinkType  = errors.New("Link type unsupported")
+)
+
+// DecodeSegment decodes the TCP segment in frame data b of link type lt.
+// It returns ErrNotTCP for other frames. Fragmented IP packets are not
+// reassembled, and are reported as ErrNotTCP.
+// The returned segment refers to b.
+func DecodeSegment(lt LinkType, b []byte) (*Segment, error) {
+	var et uint16 // ethertype
+	switch lt {
+	case LinkNull, LinkLoop:
+		if len(b) < 4 {
+			return nil, ErrTruncated
+		}
+		// address family in host or network byte order
+		af := binary.LittleEndian.Uint32(b)
+		if af > 0xffff {
+			af = binary.BigEndian.Uint32(b)
+		}
+		switch af {
+		case 2:
+			et = 0x0800
+		case 10, 24, 28, 30:
+			et = 0x86dd
+		}
+		b = b[4:]
+	case LinkEthernet:
+		if len(b) < 14 {
+			return nil, ErrTruncated
+		}
+		et, b = binary.BigEndian.Uint16(b[12:]), b[14:]
+		for et == 0x8100 || et == 0x88a8 { // VLAN tags
+			if len(b) < 4 {
+				return nil, ErrTruncated
+			}
+			et, b = binary.BigEndian.Uint16(b[2:]), b[4:]
+		}
+	case LinkRaw:
+		if len(b) > 0 {
+			switch b[0] >> 4 {
+			case 4:
+				et = 0x0800
+			case 6:
+				et = 0x86dd
+			}
+		}
+	case LinkSLL:
+		if len(b) < 16 {
+			return nil, ErrTruncated
+		}
+		et, b = binary.BigEndian.Uint16(b[14:]), b[16:]
+	case LinkSLL2:
+		if len(b) < 20 {
+			return nil, ErrTruncated
+		}
+		et, b = binary.BigEndian.Uint16(b), b[20:]
+	default:
+		return nil, ErrLinkType
+	}
+
+	s := new(Segment)
+	switch et {
+	case 0x0800:
+		if len(b) < 20 {
+			return nil, ErrTruncated
+		}
+		hl, tl := int(b[0]&0xf)*4, int(binary.BigEndian.Uint16(b[2:]))
+		if b[0]>>4 != 4 || hl < 20 || tl < hl {
+			return nil, ErrNotTCP
+		}
+		if binary.BigEndian.Uint16(b[6:])&0x3fff != 0 || b[9] != 6 {
+			// fragment or not TCP
+			return nil, ErrNotTCP
+		}
+		if len(b) < tl {
+			return nil, ErrTruncated
+		}
+		s.Src.IP, s.Dst.IP = string(b[12:16]), string(b[16:20])
+		b = b[hl:tl]
+	case 0x86dd:
+		if len(b) < 40 {
+			return nil, ErrTruncated
+		}
+		if b[0]>>4 != 6 || b[6] != 6 {
+			// extension headers are not followed
+			return nil, ErrNotTCP
+		}
+		pl := int(binary.BigEndian.Uint16(b[4:]))
+		if len(b) < 40+pl {
+			return nil, ErrTruncated
+		}
+		s.Src.IP, s.Dst.IP = string(b[8:24]), string(b[24:40])
+		b = b[40 : 40+pl]
+	default:
+		return nil, ErrNotTCP
+	}
+
+	if len(b) < 20 {
+		return nil, ErrTruncated
+	}
+	hl := int(b[12]>>4) * 4
+	if hl < 20 || len(b) < hl {
+		return nil, ErrTruncated
+	}
+	s.Src.Port = int(binary.BigEndian.Uint16(b[0:]))
+	s.Dst.Port = int(binary.BigEndian.Uint16(b[2:]))
+	s.Seq = binary.BigEndian.Uint32(b[4:])
+	fl := b[13]
+	s.FIN, s.SYN, s.RST = fl&0x01 != 0, fl&0x02 != 0, fl&0x04 != 0
+	s.Payload = b[hl:]
+	return s, nil
+}
+
+// halfStream reassembles one direction of a TCP connection.
+type halfStream struct {
+	started  bool
+	next     uint32            // next sequence number expected
+	pending  map[uint32][]byte // segments received out of order
+	npending int               // bytes in pending
+}
+
+// maxPending is the number of bytes buffered out of order,
+// before the missing data is considered lost.
+const maxPending = 1 << 20
+
+var ErrStreamGap = errors.New("TCP stream data lost")
+
+// add adds segment s to the stream, and returns the data that
+// became contiguous as a result. It returns ErrStreamGap if
+// data was lost, the stream cannot be continued in this case.
+func (h *halfStream) add(s *Segment) ([]byte, error) {
+	if s.SYN {
+		h.started, h.next = true, s.Seq+1
+		return nil, nil
+	}
+	if !h.started {
+		// stream picked up in the middle
+		h.started, h.next = true, s.Seq
+	}
+	if len(s.Payload) == 0 {
+		return nil, nil
+	}
+	d := int32(s.Seq - h.next)
+	if d > 0 {
+		if h.pending == nil {
+			h.pending = make(map[uint32][]byte)
+		}
+		if len(h.pending[s.Seq]) < len(s.Payload) {
+			h.npending += len(s.Payload) - len(h.pending[s.Seq])
+			h.pending[s.Seq] = append([]byte(nil), s.Payload...)
+		}
+		if h.npending > maxPending {
+			return nil, ErrStreamGap
+		}
+		return nil, nil
+	}
+	var out []byte
+	out = h.appendData(out, s.Seq, s.Payload)
+	for progress := true; progress && len(h.pending) != 0; {
+		progress = false
+		for seq, p := range h.pending {
+			if int32(seq-h.next) <= 0 {
+				out = h.appendData(out, seq, p)
+				delete(h.pending, seq)
+				h.npending -= len(p)
+				progress = true
+			}
+		}
+	}
+	return out, nil
+}
+
+// appendData appends the new part of p starting at seq to out.
+func (h *halfStream) appendData(out []byte, seq uint32, p []byte) []byte {
+	skip := int(h.next - seq)
+	if skip >= len(p) {
+		// retransmission
+		return out
+	}
+	p = p[skip:]
+	h.next += uint32(len(p))
+	return append(out, p...)
+}
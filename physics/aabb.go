@@ -0,0 +1,84 @@
+package physics
+
+import "math"
+
+// AABB is an axis aligned bounding box. Index 0, 1 and 2
+// of Min and Max are the X, Y and Z coordinates.
+type AABB struct {
+	Min, Max [3]float64
+}
+
+// Box returns the box with corners x0, y0, z0 and x1, y1, z1.
+func Box(x0, y0, z0, x1, y1, z1 float64) AABB {
+	return AABB{[3]float64{x0, y0, z0}, [3]float64{x1, y1, z1}}
+}
+
+// Offset returns b moved by dx, dy, dz.
+func (b AABB) Offset(dx, dy, dz float64) AABB {
+	d := [3]float64{dx, dy, dz}
+	for i := range d {
+		b.Min[i] += d[i]
+		b.Max[i] += d[i]
+	}
+	return b
+}
+
+// Expand returns b grown by dx, dy, dz on both sides of each axis.
+// Negative values shrink the box.
+func (b AABB) Expand(dx, dy, dz float64) AABB {
+	d := [3]float64{dx, dy, dz}
+	for i := range d {
+		b.Min[i] -= d[i]
+		b.Max[i] += d[i]
+	}
+	return b
+}
+
+// Extend returns b grown in the direction of dx, dy, dz, so that
+// it covers the space b sweeps when moving by them.
+func (b AABB) Extend(dx, dy, dz float64) AABB {
+	d := [3]float64{dx, dy, dz}
+	for i := range d {
+		if d[i] < 0 {
+			b.Min[i] += d[i]
+		} else {
+			b.Max[i] += d[i]
+		}
+	}
+	return b
+}
+
+// Intersects reports if b and o overlap.
+func (b AABB) Intersects(o AABB) bool {
+	for i := 0; i < 3; i++ {
+		if o.Max[i] <= b.Min[i] || o.Min[i] >= b.Max[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// clip returns how far o can move by d along axis before hitting b.
+func (b AABB) clip(o AABB, axis int, d float64) float64 {
+	for i := 0; i < 3; i++ {
+		if i != axis && (o.Max[i] <= b.Min[i] || o.Min[i] >= b.Max[i]) {
+			return d
+		}
+	}
+	if d > 0 && o.Max[axis] <= b.Min[axis] {
+		d = math.Min(d, b.Min[axis]-o.Max[axis])
+	}
+	if d < 0 && o.Min[axis] >= b.Max[axis] {
+		d = math.Max(d, b.Max[axis]-o.Min[axis])
+	}
+	return d
+}
+
+// blockRange returns the block coordinates b covers, the maximums exclusive.
+func (b AABB) blockRange() (min, max [3]int) {
+	for i := 0; i < 3; i++ {
+		min[i] = int(math.Floor(b.Min[i]))
+		max[i] = int(math.Floor(b.Max[i] + 1))
+	}
+	return
+}
@@ -0,0 +1,28 @@
+package physics
+
+import "github.com/tajtiattila/mctoy/blocks"
+
+// slipperiness returns the friction of a block when walked upon.
+func slipperiness(id int) float64 {
+	switch id {
+	case blocks.Ice, blocks.PackedIce:
+		return 0.98
+	}
+	return 0.6
+}
+
+// LadderFacing returns the yaw of a player facing the wall
+// a ladder with metadata meta is attached to.
+func LadderFacing(meta int) (yaw float32, ok bool) {
+	switch meta {
+	case 2:
+		return 0, true
+	case 3:
+		return 180, true
+	case 4:
+		return -90, true
+	case 5:
+		return 90, true
+	}
+	return 0, false
+}
@@ -0,0 +1,343 @@
+// Package physics simulates the movement of a player like
+// the Minecraft 1.7 client does.
+//
+// Coordinates are in blocks, velocities in blocks per tick,
+// and the position of a player is that of its feet.
+package physics
+
+import (
+	"github.com/tajtiattila/mctoy/blocks"
+	"math"
+)
+
+// BlockSource provides the blocks a player collides with.
+// It is implemented by *world.World.
+type BlockSource interface {
+	Block(x, y, z int) (id, meta int)
+}
+
+const (
+	// Width and Height are the dimensions of the bounding box of a player.
+	Width  = 0.6
+	Height = 1.8
+
+	// StepHeight is the height of blocks players walk up onto.
+	StepHeight = 0.5
+
+	walkSpeed     = 0.1
+	sprintSpeed   = walkSpeed * 1.3
+	airSpeed      = 0.02
+	sprintAir     = airSpeed * 1.3
+	liquidSpeed   = 0.02
+	jumpVelocity  = 0.42
+	sprintJump    = 0.2
+	gravity       = 0.08
+	drag          = 0.98
+	sneakFactor   = 0.3
+	jumpCooldown  = 10
+	ladderSpeed   = 0.15
+	ladderClimb   = 0.2
+	minVelocity   = 0.005
+	sneakEdgeStep = 0.05
+)
+
+// Input is the movement controlled by the player in a tick.
+type Input struct {
+	Forward float64 // -1 to 1, positive to move forward
+	Strafe  float64 // -1 to 1, positive to move left
+	Jump    bool
+	Sneak   bool
+	Sprint  bool
+}
+
+// Player is the state of the simulated player.
+type Player struct {
+	X, Y, Z    float64
+	VX, VY, VZ float64
+	Yaw        float32 // in degrees, movement direction
+
+	OnGround             bool // standing on a block
+	CollidedHorizontally bool // movement blocked in the last tick
+	CollidedVertically   bool
+	InWater              bool
+	InLava               bool
+
+	jumpTicks int  // until the player may jump again
+	inWeb     bool // slowed down by a web during the next move
+}
+
+// BoundingBox returns the bounding box of p.
+func (p *Player) BoundingBox() AABB {
+	return Box(p.X-Width/2, p.Y, p.Z-Width/2, p.X+Width/2, p.Y+Height, p.Z+Width/2)
+}
+
+// Step advances p by one tick in the world of blocks w.
+// Liquid currents, potion effects and entities are not simulated.
+func (p *Player) Step(w BlockSource, in Input) {
+	if p.jumpTicks > 0 {
+		p.jumpTicks--
+	}
+	for _, v := range []*float64{&p.VX, &p.VY, &p.VZ} {
+		if math.Abs(*v) < minVelocity {
+			*v = 0
+		}
+	}
+
+	bb := p.BoundingBox()
+	p.InWater = anyBlock(w, bb.Expand(-0.001, -0.401, -0.001), blocks.IsWater)
+	p.InLava = anyBlock(w, bb.Expand(-0.1, -0.4, -0.1), blocks.IsLava)
+
+	if in.Jump {
+		switch {
+		case p.InWater || p.InLava:
+			p.VY += 0.04
+		case p.OnGround && p.jumpTicks == 0:
+			p.jump(in.Sprint)
+			p.jumpTicks = jumpCooldown
+		}
+	} else {
+		p.jumpTicks = 0
+	}
+
+	strafe, forward := in.Strafe, in.Forward
+	if in.Sneak {
+		strafe, forward = strafe*sneakFactor, forward*sneakFactor
+	}
+	strafe, forward = strafe*drag, forward*drag
+
+	if p.InWater || p.InLava {
+		y := p.Y
+		p.accelerate(strafe, forward, liquidSpeed)
+		p.move(w, p.VX, p.VY, p.VZ, in.Sneak)
+		f := 0.8
+		if !p.InWater {
+			f = 0.5
+		}
+		p.VX, p.VY, p.VZ = p.VX*f, p.VY*f-0.02, p.VZ*f
+		if p.CollidedHorizontally && !p.liquidOrBlocked(w, p.VX, p.VY+0.6-p.Y+y, p.VZ) {
+			// climb out at the shore
+			p.VY = 0.3
+		}
+		return
+	}
+
+	friction := 0.91
+	if p.OnGround {
+		id, _ := w.Block(floor(p.X), floor(p.Y)-1, floor(p.Z))
+		friction = slipperiness(id) * 0.91
+	}
+	var speed float64
+	switch {
+	case p.OnGround && in.Sprint:
+		speed = sprintSpeed * 0.16277136 / (friction * friction * friction)
+	case p.OnGround:
+		speed = walkSpeed * 0.16277136 / (friction * friction * friction)
+	case in.Sprint:
+		speed = sprintAir
+	default:
+		speed = airSpeed
+	}
+	p.accelerate(strafe, forward, speed)
+
+	if p.onLadder(w) {
+		p.VX = math.Max(-ladderSpeed, math.Min(p.VX, ladderSpeed))
+		p.VZ = math.Max(-ladderSpeed, math.Min(p.VZ, ladderSpeed))
+		if p.VY < -ladderSpeed {
+			p.VY = -ladderSpeed
+		}
+		if in.Sneak && p.VY < 0 {
+			p.VY = 0
+		}
+	}
+	p.move(w, p.VX, p.VY, p.VZ, in.Sneak)
+	if p.CollidedHorizontally && p.onLadder(w) {
+		p.VY = ladderClimb
+	}
+	p.VY = (p.VY - gravity) * drag
+	p.VX *= friction
+	p.VZ *= friction
+}
+
+func (p *Player) onLadder(w BlockSource) bool {
+	id, _ := w.Block(floor(p.X), floor(p.Y), floor(p.Z))
+	return blocks.IsClimbable(id)
+}
+
+func (p *Player) jump(sprint bool) {
+	p.VY = jumpVelocity
+	if sprint {
+		yaw := float64(p.Yaw) * math.Pi / 180
+		p.VX -= math.Sin(yaw) * sprintJump
+		p.VZ += math.Cos(yaw) * sprintJump
+	}
+}
+
+// accelerate adds the movement input to the velocity of p.
+func (p *Player) accelerate(strafe, forward, speed float64) {
+	d := strafe*strafe + forward*forward
+	if d < 1e-4 {
+		return
+	}
+	d = math.Max(math.Sqrt(d), 1)
+	strafe, forward = strafe*speed/d, forward*speed/d
+	yaw := float64(p.Yaw) * math.Pi / 180
+	sin, cos := math.Sin(yaw), math.Cos(yaw)
+	p.VX += strafe*cos - forward*sin
+	p.VZ += forward*cos + strafe*sin
+}
+
+// move moves p by dx, dy, dz, stopping at blocks in the way.
+func (p *Player) move(w BlockSource, dx, dy, dz float64, sneak bool) {
+	if p.inWeb {
+		p.inWeb = false
+		dx, dy, dz = dx*0.25, dy*0.05, dz*0.25
+		p.VX, p.VY, p.VZ = 0, 0, 0
+	}
+	bb := p.BoundingBox()
+
+	if p.OnGround && sneak {
+		// don't walk off edges
+		over := func(dx, dz float64) bool { return len(collisions(w, bb.Offset(dx, -1, dz))) == 0 }
+		for dx != 0 && over(dx, 0) {
+			dx = sneakClip(dx)
+		}
+		for dz != 0 && over(0, dz) {
+			dz = sneakClip(dz)
+		}
+		for dx != 0 && dz != 0 && over(dx, dz) {
+			dx, dz = sneakClip(dx), sneakClip(dz)
+		}
+	}
+	wantX, wantY, wantZ := dx, dy, dz
+
+	nbb, dx, dy, dz := sweep(collisions(w, bb.Extend(dx, dy, dz)), bb, dx, dy, dz)
+
+	if (p.OnGround || (wantY != dy && wantY < 0)) && (wantX != dx || wantZ != dz) {
+		// try stepping up onto the block in the way
+		v := collisions(w, bb.Extend(wantX, StepHeight, wantZ))
+		sbb, sx, sy, sz := sweep(v, bb, wantX, StepHeight, wantZ)
+		down := -StepHeight
+		for _, b := range v {
+			down = b.clip(sbb, 1, down)
+		}
+		sbb = sbb.Offset(0, down, 0)
+		sy += down
+		if sx*sx+sz*sz > dx*dx+dz*dz {
+			nbb, dx, dy, dz = sbb, sx, sy, sz
+		}
+	}
+
+	p.X = (nbb.Min[0] + nbb.Max[0]) / 2
+	p.Y = nbb.Min[1]
+	p.Z = (nbb.Min[2] + nbb.Max[2]) / 2
+	p.CollidedHorizontally = wantX != dx || wantZ != dz
+	p.CollidedVertically = wantY != dy
+	p.OnGround = wantY != dy && wantY < 0
+	if wantX != dx {
+		p.VX = 0
+	}
+	if wantY != dy {
+		p.VY = 0
+	}
+	if wantZ != dz {
+		p.VZ = 0
+	}
+	p.touchBlocks(w, nbb.Expand(-0.001, -0.001, -0.001))
+}
+
+// touchBlocks applies the effects of blocks touching bb.
+func (p *Player) touchBlocks(w BlockSource, bb AABB) {
+	min, max := bb.blockRange()
+	for x := min[0]; x < max[0]; x++ {
+		for y := min[1]; y < max[1]; y++ {
+			for z := min[2]; z < max[2]; z++ {
+				switch id, _ := w.Block(x, y, z); id {
+				case blocks.SoulSand:
+					p.VX *= 0.4
+					p.VZ *= 0.4
+				case blocks.Web:
+					p.inWeb = true
+				}
+			}
+		}
+	}
+}
+
+// liquidOrBlocked reports if the player moved by dx, dy, dz
+// would collide with blocks or be in a liquid.
+func (p *Player) liquidOrBlocked(w BlockSource, dx, dy, dz float64) bool {
+	bb := p.BoundingBox().Offset(dx, dy, dz)
+	return len(collisions(w, bb)) != 0 ||
+		anyBlock(w, bb, func(id int) bool { return blocks.IsWater(id) || blocks.IsLava(id) })
+}
+
+// sweep moves bb by dx, dy, dz along the Y, X then Z axes,
+// stopping at the boxes v, and returns the result and the
+// distance moved on each axis.
+func sweep(v []AABB, bb AABB, dx, dy, dz float64) (AABB, float64, float64, float64) {
+	for _, b := range v {
+		dy = b.clip(bb, 1, dy)
+	}
+	bb = bb.Offset(0, dy, 0)
+	for _, b := range v {
+		dx = b.clip(bb, 0, dx)
+	}
+	bb = bb.Offset(dx, 0, 0)
+	for _, b := range v {
+		dz = b.clip(bb, 2, dz)
+	}
+	bb = bb.Offset(0, 0, dz)
+	return bb, dx, dy, dz
+}
+
+// sneakClip reduces d by sneakEdgeStep towards zero.
+func sneakClip(d float64) float64 {
+	switch {
+	case d < sneakEdgeStep && d >= -sneakEdgeStep:
+		return 0
+	case d > 0:
+		return d - sneakEdgeStep
+	}
+	return d + sneakEdgeStep
+}
+
+// collisions returns the collision boxes of blocks intersecting bb.
+func collisions(w BlockSource, bb AABB) []AABB {
+	var v []AABB
+	min, max := bb.blockRange()
+	// fences, walls and doors reach into the block above
+	for x := min[0]; x < max[0]; x++ {
+		for y := min[1] - 1; y < max[1]; y++ {
+			for z := min[2]; z < max[2]; z++ {
+				id, meta := w.Block(x, y, z)
+				for _, c := range blocks.Boxes(id, meta) {
+					b := AABB(c).Offset(float64(x), float64(y), float64(z))
+					if b.Intersects(bb) {
+						v = append(v, b)
+					}
+				}
+			}
+		}
+	}
+	return v
+}
+
+// anyBlock reports if there is a block within the range of bb
+// for which f returns true.
+func anyBlock(w BlockSource, bb AABB, f func(id int) bool) bool {
+	min, max := bb.blockRange()
+	for x := min[0]; x < max[0]; x++ {
+		for y := min[1]; y < max[1]; y++ {
+			for z := min[2]; z < max[2]; z++ {
+				if id, _ := w.Block(x, y, z); f(id) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func floor(v float64) int {
+	return int(math.Floor(v))
+}
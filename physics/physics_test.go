@@ -0,0 +1,155 @@
+package physics
+
+import (
+	"github.com/tajtiattila/mctoy/blocks"
+	"math"
+	"testing"
+)
+
+type testWorld map[[3]int][2]int
+
+func (w testWorld) Block(x, y, z int) (id, meta int) {
+	b := w[[3]int{x, y, z}]
+	return b[0], b[1]
+}
+
+func (w testWorld) set(x, y, z, id, meta int) {
+	w[[3]int{x, y, z}] = [2]int{id, meta}
+}
+
+// floorWorld returns a world with stone at y = 63 around the origin.
+func floorWorld() testWorld {
+	w := make(testWorld)
+	for x := -16; x < 16; x++ {
+		for z := -16; z < 16; z++ {
+			w.set(x, 63, z, 1, 0)
+		}
+	}
+	return w
+}
+
+func run(p *Player, w BlockSource, in Input, n int) {
+	for i := 0; i < n; i++ {
+		p.Step(w, in)
+	}
+}
+
+func TestFall(t *testing.T) {
+	w := floorWorld()
+	p := &Player{X: 0.5, Y: 70, Z: 0.5}
+	p.Step(w, Input{})
+	if want := -0.08 * 0.98; math.Abs(p.VY-want) > 1e-9 || p.Y != 70 || p.OnGround {
+		t.Errorf("first tick: y %v vy %v, want 70 %v", p.Y, p.VY, want)
+	}
+	run(p, w, Input{}, 40)
+	if p.Y != 64 || !p.OnGround || p.VY > 0 {
+		t.Errorf("landed at %v onground %v vy %v", p.Y, p.OnGround, p.VY)
+	}
+	run(p, w, Input{}, 5)
+	if p.Y != 64 || !p.OnGround {
+		t.Errorf("standing at %v onground %v", p.Y, p.OnGround)
+	}
+}
+
+func TestJump(t *testing.T) {
+	w := floorWorld()
+	p := &Player{X: 0.5, Y: 64, Z: 0.5, OnGround: true}
+	p.Step(w, Input{Jump: true})
+	if p.Y != 64.42 || p.OnGround {
+		t.Fatalf("after jump y %v onground %v", p.Y, p.OnGround)
+	}
+	top := p.Y
+	for i := 0; i < 20; i++ {
+		p.Step(w, Input{})
+		if p.Y > top {
+			top = p.Y
+		}
+	}
+	if top < 65.2 || top > 65.3 || p.Y != 64 || !p.OnGround {
+		t.Errorf("jump top %v, end %v onground %v", top, p.Y, p.OnGround)
+	}
+}
+
+func TestWalk(t *testing.T) {
+	w := floorWorld()
+	p := &Player{X: 0.5, Y: 64, Z: 0.5, OnGround: true}
+	run(p, w, Input{Forward: 1}, 20)
+	// yaw 0 is towards +Z, walking speed is about 4.3 blocks/s
+	if math.Abs(p.X-0.5) > 1e-9 || p.Z < 4.3 || p.Z > 4.6 || p.Y != 64 {
+		t.Errorf("walked to %v %v %v", p.X, p.Y, p.Z)
+	}
+	s := &Player{X: 0.5, Y: 64, Z: 0.5, OnGround: true}
+	run(s, w, Input{Forward: 1, Sprint: true}, 20)
+	if s.Z < p.Z*1.25 {
+		t.Errorf("sprinted to %v, walked to %v", s.Z, p.Z)
+	}
+}
+
+func TestWallAndStep(t *testing.T) {
+	w := floorWorld()
+	w.set(0, 64, 3, 1, 0)
+	w.set(0, 65, 3, 1, 0)
+	p := &Player{X: 0.5, Y: 64, Z: 0.5, OnGround: true}
+	run(p, w, Input{Forward: 1}, 20)
+	if p.Z != 3-Width/2 || !p.CollidedHorizontally || p.VZ != 0 {
+		t.Errorf("at wall z %v collided %v vz %v", p.Z, p.CollidedHorizontally, p.VZ)
+	}
+
+	w = floorWorld()
+	w.set(0, 64, 3, blocks.StoneSlab, 0)
+	p = &Player{X: 0.5, Y: 64, Z: 0.5, OnGround: true}
+	run(p, w, Input{Forward: 1}, 20)
+	if p.Y != 64.5 || p.Z < 3.5 {
+		t.Errorf("after step up y %v z %v", p.Y, p.Z)
+	}
+}
+
+func TestSneakEdge(t *testing.T) {
+	w := make(testWorld)
+	for z := 0; z < 3; z++ {
+		w.set(0, 63, z, 1, 0)
+	}
+	p := &Player{X: 0.5, Y: 64, Z: 0.5, OnGround: true}
+	run(p, w, Input{Forward: 1, Sneak: true}, 100)
+	if p.Y != 64 || p.Z > 3+Width/2 || p.Z < 3 {
+		t.Errorf("sneaked to y %v z %v", p.Y, p.Z)
+	}
+	run(p, w, Input{Forward: 1}, 40)
+	if p.Y >= 63 {
+		t.Errorf("walked off edge y %v z %v", p.Y, p.Z)
+	}
+}
+
+func TestWaterAndLadder(t *testing.T) {
+	w := floorWorld()
+	for y := 64; y < 68; y++ {
+		w.set(0, y, 0, blocks.Water, 0)
+	}
+	p := &Player{X: 0.5, Y: 67, Z: 0.5}
+	run(p, w, Input{}, 3)
+	if !p.InWater || p.VY < -0.1 {
+		t.Errorf("in water %v vy %v", p.InWater, p.VY)
+	}
+	run(p, w, Input{Jump: true}, 40)
+	if p.Y < 66 {
+		t.Errorf("swimming up y %v", p.Y)
+	}
+
+	w = floorWorld()
+	for y := 64; y < 70; y++ {
+		w.set(0, y, 1, blocks.Ladder, 2)
+		w.set(0, y, 2, 1, 0)
+	}
+	p = &Player{X: 0.5, Y: 64, Z: 1.5, OnGround: true}
+	run(p, w, Input{Forward: 1}, 20)
+	// (0.2 - 0.08) * 0.98 blocks per tick
+	if p.Y < 66.2 || math.Abs(p.VY-0.1176) > 1e-9 {
+		t.Errorf("climbed to y %v vy %v", p.Y, p.VY)
+	}
+	run(p, w, Input{Sneak: true}, 5)
+	y := p.Y
+	run(p, w, Input{Sneak: true}, 20)
+	if p.Y != y {
+		t.Errorf("sneaking on ladder moved from %v to %v", y, p.Y)
+	}
+}
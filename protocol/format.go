@@ -0,0 +1,227 @@
+package protocol
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+
+PacketString formats packets for logs. Fields are printed with their names,
+and are converted according to their fmt struct tags:
+
+ Tag        Meaning
+ ===        =======
+ fixed      fixed-point number with 5 fraction bits, shown in blocks
+ angle      angle in steps of 1/256 of a full turn, shown in degrees
+ velocity   velocity in 1/8000 blocks per tick, shown in blocks per tick
+ div=N      value divided by N
+
+*/
+
+const (
+	formatMaxBytes = 16 // bytes shown from byte slices
+	formatMaxElems = 32 // elements shown from other slices
+)
+
+// PacketString returns packet p formatted for humans, eg.
+//
+//	SpawnMob{EntityID:42 Type:50 X:-12.5 Y:64 Z:3.25 Pitch:0° ...}
+//
+// Slots are shown as id:damage xcount, and long slices are truncated.
+func PacketString(p interface{}) string {
+	v := reflect.ValueOf(p)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "nil"
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return "nil"
+	}
+	var buf bytes.Buffer
+	buf.WriteString(v.Type().Name())
+	if v.Kind() != reflect.Struct {
+		buf.WriteByte('(')
+		formatValue(&buf, v, "")
+		buf.WriteByte(')')
+		return buf.String()
+	}
+	formatValue(&buf, v, "")
+	return buf.String()
+}
+
+var stringerType = reflect.TypeOf((*fmt.Stringer)(nil)).Elem()
+
+func formatValue(w *bytes.Buffer, v reflect.Value, hint string) {
+	if hint != "" && formatHint(w, v, hint) {
+		return
+	}
+	if v.Type().Implements(stringerType) && v.CanInterface() {
+		if v.Kind() == reflect.Ptr && v.IsNil() {
+			w.WriteString("nil")
+			return
+		}
+		w.WriteString(v.Interface().(fmt.Stringer).String())
+		return
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			w.WriteString("nil")
+			return
+		}
+		formatValue(w, v.Elem(), hint)
+	case reflect.Struct:
+		rt := v.Type()
+		w.WriteByte('{')
+		for i := 0; i < rt.NumField(); i++ {
+			f := rt.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			if i != 0 {
+				w.WriteByte(' ')
+			}
+			w.WriteString(f.Name)
+			w.WriteByte(':')
+			formatValue(w, v.Field(i), f.Tag.Get("fmt"))
+		}
+		w.WriteByte('}')
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			formatBytes(w, v)
+			return
+		}
+		w.WriteByte('[')
+		n := v.Len()
+		for i := 0; i < n && i < formatMaxElems; i++ {
+			if i != 0 {
+				w.WriteByte(' ')
+			}
+			formatValue(w, v.Index(i), hint)
+		}
+		if n > formatMaxElems {
+			fmt.Fprintf(w, " ... %d more", n-formatMaxElems)
+		}
+		w.WriteByte(']')
+	case reflect.String:
+		w.WriteString(strconv.Quote(v.String()))
+	case reflect.Float32:
+		w.WriteString(strconv.FormatFloat(v.Float(), 'g', -1, 32))
+	case reflect.Float64:
+		w.WriteString(strconv.FormatFloat(v.Float(), 'g', -1, 64))
+	default:
+		fmt.Fprint(w, v.Interface())
+	}
+}
+
+// formatHint formats v according to fmt struct tag hint,
+// and reports whether v was formatted.
+func formatHint(w *bytes.Buffer, v reflect.Value, hint string) bool {
+	var f float64
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		f = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		f = float64(v.Uint())
+	default:
+		return false
+	}
+	var sfx string
+	switch {
+	case hint == "fixed":
+		f /= 32
+	case hint == "angle":
+		f, sfx = f*360/256, "°"
+	case hint == "velocity":
+		f, sfx = f/8000, "/t"
+	case strings.HasPrefix(hint, "div="):
+		d, err := strconv.ParseFloat(hint[4:], 64)
+		if err != nil || d == 0 {
+			return false
+		}
+		f /= d
+	default:
+		return false
+	}
+	w.WriteString(strconv.FormatFloat(f, 'g', -1, 64))
+	w.WriteString(sfx)
+	return true
+}
+
+func formatBytes(w *bytes.Buffer, v reflect.Value) {
+	n := v.Len()
+	m := n
+	if m > formatMaxBytes {
+		m = formatMaxBytes
+	}
+	b := make([]byte, m)
+	for i := range b {
+		b[i] = byte(v.Index(i).Uint())
+	}
+	if v.Kind() == reflect.Array {
+		w.WriteString(hex.EncodeToString(b))
+		return
+	}
+	fmt.Fprintf(w, "[%d bytes", n)
+	if n != 0 {
+		w.WriteByte(' ')
+		w.WriteString(hex.EncodeToString(b))
+		if n > m {
+			w.WriteString("...")
+		}
+	}
+	w.WriteByte(']')
+}
+
+// String returns s as id:damage xcount, or "empty".
+func (s Slot) String() string {
+	if s.Id == 0xffff {
+		return "empty"
+	}
+	str := fmt.Sprintf("%d:%d x%d", s.Id, s.Damage, s.Count)
+	if len(s.Tag) != 0 {
+		str += fmt.Sprintf(" +tag[%d bytes]", len(s.Tag))
+	}
+	return str
+}
+
+// String returns the metadata entries ordered by index.
+func (d Metadata) String() string {
+	var idx []int
+	for i := range d {
+		idx = append(idx, i)
+	}
+	sort.Ints(idx)
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for n, i := range idx {
+		if n != 0 {
+			buf.WriteByte(' ')
+		}
+		fmt.Fprintf(&buf, "%d:", i)
+		if d[i] == nil {
+			buf.WriteString("nil")
+			continue
+		}
+		formatValue(&buf, reflect.ValueOf(d[i]), "")
+	}
+	buf.WriteByte('}')
+	return buf.String()
+}
+
+// String returns the position of the block relative
+// to its chunk, and its block id and metadata.
+func (r Record) String() string {
+	return fmt.Sprintf("(%d,%d,%d)=%d:%d", r>>28, r>>16&0xff, r>>24&0xf, r>>4&0xfff, r&0xf)
+}
+
+func (p XYZint) String() string { return fmt.Sprintf("(%d,%d,%d)", p.X, p.Y, p.Z) }
+func (p XYZ8) String() string   { return fmt.Sprintf("(%d,%d,%d)", p.X, p.Y, p.Z) }
@@ -0,0 +1,45 @@
+package protocol
+
+import (
+	"testing"
+)
+
+func TestPacketString(t *testing.T) {
+	tests := []struct {
+		p    interface{}
+		want string
+	}{
+		{
+			&SpawnPlayer{EntityID: 7, PlayerName: "Steve", X: -400, Y: 2048, Z: 16, Yaw: 64, Pitch: -32,
+				Values: Metadata{6: float32(20), 0: int8(0)}},
+			`SpawnPlayer{EntityID:7 PlayerUUID:"" PlayerName:"Steve" X:-12.5 Y:64 Z:0.5 Yaw:90° Pitch:-45° CurrentItem:0 Values:{0:0 6:20}}`,
+		},
+		{
+			&SetSlot{WindowID: 0, Slot: 36, SlotData: Slot{Id: 276, Damage: 5, Count: 1}},
+			`SetSlot{WindowID:0 Slot:36 SlotData:276:5 x1}`,
+		},
+		{
+			&WindowItems{SlotData: []Slot{{Id: 0xffff}, {Id: 1, Count: 64}}},
+			`WindowItems{WindowID:0 SlotData:[empty 1:0 x64]}`,
+		},
+		{
+			&EntityVelocity{EntityID: 1, VelocityY: -800},
+			`EntityVelocity{EntityID:1 VelocityX:0/t VelocityY:-0.1/t VelocityZ:0/t}`,
+		},
+		{
+			&ChunkData{ChunkX: 1, CompressedData: make([]byte, 100)},
+			`ChunkData{ChunkX:1 ChunkZ:0 GroundUpContinuous:false PrimaryBitMap:0 AddBitMap:0 ` +
+				`CompressedData:[100 bytes 00000000000000000000000000000000...]}`,
+		},
+		{
+			&MultiBlockChange{RecordCount: 1, Records: []Record{0x1f400231}},
+			`MultiBlockChange{ChunkX:0 ChunkZ:0 RecordCount:1 Records:[(1,64,15)=35:1]}`,
+		},
+		{nil, "nil"},
+	}
+	for _, tt := range tests {
+		if got := PacketString(tt.p); got != tt.want {
+			t.Errorf("got  %s\nwant %s", got, tt.want)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package protocol
 
 import (
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"reflect"
@@ -70,8 +71,11 @@ func (hs *HostState) Encode(buf []byte, packet interface{}) (n int, err error) {
 // Decode returns the packet encoded in buf.
 func (hs *HostState) Decode(buf []byte) (p interface{}, err error) {
 	c := MakeCoder(buf)
-	id := c.Varint()
-	pi := hs.Recv[id]
+	id, l := binary.Uvarint(buf)
+	var pi *PacketInfo
+	if l > 0 && id < uint64(len(hs.Recv)) {
+		pi, c.pos = hs.Recv[id], l
+	}
 	if pi == nil {
 		return nil, &ErrInvalidPacketId{
 			hs.ht,
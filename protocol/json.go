@@ -0,0 +1,290 @@
+package protocol
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/tajtiattila/mctoy/nbt"
+	"reflect"
+	"sort"
+)
+
+/*
+
+Packets are represented in JSON with their type name, packet id and fields:
+
+ {"type":"SpawnMob","id":15,"fields":{"EntityID":42,"Type":50,...}}
+
+Fields are named as in the packet structs. Byte slices are base64 encoded,
+Slot and Metadata values are represented as shown by their MarshalJSON
+methods.
+
+*/
+
+type packetJSON struct {
+	Type   string          `json:"type"`
+	Id     *int            `json:"id,omitempty"`
+	Fields json.RawMessage `json:"fields,omitempty"`
+}
+
+var (
+	ErrJSONPacketType = errors.New("JSON packet type invalid")
+	ErrJSONPacketId   = errors.New("JSON packet id mismatch")
+)
+
+// EncodeJSON returns the JSON representation of packet p
+// to be sent in this HostState.
+func (hs *HostState) EncodeJSON(p interface{}) ([]byte, error) {
+	rt := packetStructType(p)
+	pi, ok := hs.Send[rt]
+	if !ok {
+		return nil, &ErrInvalidPacketId{
+			hs.ht,
+			hs.xs,
+			fmt.Sprint("type invalid for state: ", rt.String()),
+		}
+	}
+	return encodeJSON(pi.Id, rt, p)
+}
+
+// DecodeJSON returns the packet received in this HostState
+// from its JSON representation in b.
+func (hs *HostState) DecodeJSON(b []byte) (interface{}, error) {
+	var pj packetJSON
+	if err := json.Unmarshal(b, &pj); err != nil {
+		return nil, err
+	}
+	for _, pi := range hs.Recv {
+		if pi != nil && pi.Rt.Name() == pj.Type {
+			if pj.Id != nil && *pj.Id != pi.Id {
+				return nil, ErrJSONPacketId
+			}
+			return decodeJSONFields(pi.Rt, pj.Fields)
+		}
+	}
+	return nil, &ErrInvalidPacketId{
+		hs.ht,
+		hs.xs,
+		fmt.Sprint("type invalid for state: ", pj.Type),
+	}
+}
+
+// EncodeJSON returns the JSON representation of packet p. The packet id is
+// the one p is first registered with, in the order of CxnStates, and
+// clientbound before serverbound. Use HostState.EncodeJSON for packets
+// having different ids depending on the direction.
+func EncodeJSON(p interface{}) ([]byte, error) {
+	rt := packetStructType(p)
+	ids := packetIds(rt)
+	if len(ids) == 0 {
+		return nil, ErrJSONPacketType
+	}
+	return encodeJSON(ids[0], rt, p)
+}
+
+// DecodeJSON returns the packet in its JSON representation b.
+// The packet id, if present, must be valid for the packet type.
+func DecodeJSON(b []byte) (interface{}, error) {
+	var pj packetJSON
+	if err := json.Unmarshal(b, &pj); err != nil {
+		return nil, err
+	}
+	rt := PacketType(pj.Type)
+	if rt == nil {
+		return nil, ErrJSONPacketType
+	}
+	if pj.Id != nil {
+		ok := false
+		for _, id := range packetIds(rt) {
+			ok = ok || id == *pj.Id
+		}
+		if !ok {
+			return nil, ErrJSONPacketId
+		}
+	}
+	return decodeJSONFields(rt, pj.Fields)
+}
+
+func packetStructType(p interface{}) reflect.Type {
+	rt := reflect.TypeOf(p)
+	for rt != nil && rt.Kind() == reflect.Ptr {
+		rt = rt.Elem()
+	}
+	return rt
+}
+
+// packetIds returns the ids packet type rt is registered with.
+func packetIds(rt reflect.Type) []int {
+	var ids []int
+	for _, v := range PacketData {
+		for _, hs := range v {
+			if pi, ok := hs.Send[rt]; ok {
+				ids = append(ids, pi.Id)
+			}
+		}
+	}
+	return ids
+}
+
+func encodeJSON(id int, rt reflect.Type, p interface{}) ([]byte, error) {
+	fields, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(packetJSON{Type: rt.Name(), Id: &id, Fields: fields})
+}
+
+func decodeJSONFields(rt reflect.Type, fields json.RawMessage) (interface{}, error) {
+	pv := reflect.New(rt)
+	if len(fields) != 0 {
+		if err := json.Unmarshal(fields, pv.Interface()); err != nil {
+			return nil, err
+		}
+	}
+	return pv.Interface(), nil
+}
+
+type slotJSON struct {
+	Id     uint16
+	Count  byte
+	Damage uint16
+	Tag    interface{} `json:",omitempty"`
+}
+
+// MarshalJSON implements json.Marshaler. Empty slots are represented as
+// null. The NBT data in Tag is shown as in package nbt, or base64 encoded
+// if it can't be decoded.
+func (s Slot) MarshalJSON() ([]byte, error) {
+	if s.Id == 0xffff {
+		return []byte("null"), nil
+	}
+	sj := slotJSON{Id: s.Id, Count: s.Count, Damage: s.Damage}
+	if len(s.Tag) != 0 {
+		if t, _, err := nbt.Read(s.Tag); err == nil {
+			sj.Tag = t
+		} else {
+			sj.Tag = s.Tag
+		}
+	}
+	return json.Marshal(sj)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Slot) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		*s = Slot{Id: 0xffff}
+		return nil
+	}
+	var sj struct {
+		slotJSON
+		Tag json.RawMessage
+	}
+	if err := json.Unmarshal(b, &sj); err != nil {
+		return err
+	}
+	*s = Slot{Id: sj.Id, Count: sj.Count, Damage: sj.Damage}
+	if len(sj.Tag) == 0 || string(sj.Tag) == "null" {
+		return nil
+	}
+	if sj.Tag[0] == '"' {
+		return json.Unmarshal(sj.Tag, &s.Tag)
+	}
+	var t nbt.Tag
+	if err := json.Unmarshal(sj.Tag, &t); err != nil {
+		return err
+	}
+	tb, err := nbt.EncodeTag(t)
+	if err == nil {
+		s.Tag, err = nbt.Compress(tb, nbt.Gzip)
+	}
+	return err
+}
+
+type metadataEntry struct {
+	Index int             `json:"index"`
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// MarshalJSON implements json.Marshaler. Metadata is represented as
+// an array of entries ordered by index, eg.
+//
+//	[{"index":0,"type":"byte","value":0},{"index":6,"type":"float","value":20}]
+//
+// Types are byte, short, int, float, string, slot and xyz.
+func (d Metadata) MarshalJSON() ([]byte, error) {
+	var idx []int
+	for i := range d {
+		idx = append(idx, i)
+	}
+	sort.Ints(idx)
+	v := make([]metadataEntry, 0, len(idx))
+	for _, i := range idx {
+		var typ string
+		switch d[i].(type) {
+		case int8:
+			typ = "byte"
+		case int16:
+			typ = "short"
+		case int32:
+			typ = "int"
+		case float32:
+			typ = "float"
+		case string:
+			typ = "string"
+		case *Slot:
+			typ = "slot"
+		case *XYZint:
+			typ = "xyz"
+		default:
+			return nil, fmt.Errorf("Metadata type %T invalid", d[i])
+		}
+		b, err := json.Marshal(d[i])
+		if err != nil {
+			return nil, err
+		}
+		v = append(v, metadataEntry{i, typ, b})
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (d *Metadata) UnmarshalJSON(b []byte) error {
+	var v []metadataEntry
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	m := make(Metadata)
+	for _, e := range v {
+		var p interface{}
+		switch e.Type {
+		case "byte":
+			p = new(int8)
+		case "short":
+			p = new(int16)
+		case "int":
+			p = new(int32)
+		case "float":
+			p = new(float32)
+		case "string":
+			p = new(string)
+		case "slot":
+			p = new(Slot)
+		case "xyz":
+			p = new(XYZint)
+		default:
+			return fmt.Errorf("Metadata type %q invalid", e.Type)
+		}
+		if err := json.Unmarshal(e.Value, p); err != nil {
+			return err
+		}
+		switch p.(type) {
+		case *Slot, *XYZint:
+			m[e.Index] = p
+		default:
+			m[e.Index] = reflect.ValueOf(p).Elem().Interface()
+		}
+	}
+	*d = m
+	return nil
+}
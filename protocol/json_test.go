@@ -0,0 +1,86 @@
+package protocol
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPacketJSON(t *testing.T) {
+	packets := []interface{}{
+		&SpawnMob{EntityID: 42, Type: 50, Values: Metadata{
+			0:  int8(1),
+			1:  int16(300),
+			6:  float32(20),
+			10: "name",
+			12: &Slot{Id: 0xffff},
+			13: &Slot{Id: 1, Count: 64, Damage: 3},
+			17: &XYZint{1, 2, 3},
+		}},
+		&SetSlot{WindowID: 0, Slot: 36, SlotData: Slot{Id: 0xffff}},
+		&WindowItems{SlotData: []Slot{{Id: 1, Count: 1}, {Id: 0xffff}}},
+		&EncryptionRequest{ServerId: "x", PublicKey: []byte{1, 2, 3}, VerifyToken: []byte("tokn")},
+	}
+	for _, p := range packets {
+		b, err := EncodeJSON(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		q, err := DecodeJSON(b)
+		if err != nil {
+			t.Fatal(string(b), err)
+		}
+		if !reflect.DeepEqual(p, q) {
+			t.Errorf("JSON round trip failed:\n%s\n%#v\n%#v", b, p, q)
+		}
+	}
+
+	b, err := EncodeJSON(&SpawnMob{EntityID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(b), `{"type":"SpawnMob","id":15,"fields":{`) {
+		t.Error("unexpected encoding:", string(b))
+	}
+
+	hs := GetHostState(Client, StatePlay)
+	b, err = hs.EncodeJSON(&CloseWindow{WindowID: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `"id":13`) {
+		t.Error("unexpected serverbound id:", string(b))
+	}
+	if _, err = hs.DecodeJSON(b); err == nil {
+		t.Error("client decoded packet it sent")
+	}
+	if _, err = GetHostState(Server, StatePlay).DecodeJSON(b); err != nil {
+		t.Error(err)
+	}
+
+	for _, s := range []string{
+		`{"type":"NoSuchPacket"}`,
+		`{"type":"SpawnMob","id":16}`,
+		`{"type":"SpawnMob","fields":{"Values":[{"index":0,"type":"long","value":1}]}}`,
+	} {
+		if _, err := DecodeJSON([]byte(s)); err == nil {
+			t.Error("expected error for", s)
+		}
+	}
+}
+
+func TestMetadataPacket(t *testing.T) {
+	p := &EntityMetadata{EntityID: 7, Values: Metadata{17: &XYZint{1, 2, 3}}}
+	buf := make([]byte, 256)
+	n, err := GetHostState(Server, StatePlay).Encode(buf, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := GetHostState(Client, StatePlay).Decode(buf[:n])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(p, q) {
+		t.Errorf("got %#v, want %#v", q, p)
+	}
+}
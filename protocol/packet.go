@@ -4,6 +4,9 @@ import (
 	"fmt"
 )
 
+// ProtocolVersion is the protocol version implemented by this package.
+const ProtocolVersion = 4 // 1.7.2
+
 type CxnState byte
 
 const (
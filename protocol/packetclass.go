@@ -171,5 +171,14 @@ func initPackets(state CxnState, sender HostType, px ...interface{}) {
 		pinf := PacketInfo{Id: id, Rt: rt, Write: pw, Read: pr}
 		vr.Recv[id] = &pinf
 		vs.Send[rt] = pinf
+		packetTypes[rt.Name()] = rt
 	}
 }
+
+var packetTypes = map[string]reflect.Type{}
+
+// PacketType returns the packet type with the given name,
+// or nil if there is no such packet.
+func PacketType(name string) reflect.Type {
+	return packetTypes[name]
+}
@@ -96,11 +96,11 @@ type SpawnPlayer struct {
 	EntityID    uint   // Player's Entity ID
 	PlayerUUID  string // Player's UUID
 	PlayerName  string // Player's Name
-	X           int32  // Player X as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
-	Y           int32  // Player X as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
-	Z           int32  // Player X as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
-	Yaw         int8   // Player rotation as a packed byte
-	Pitch       int8   // Player rotation as a packet byte
+	X           int32  `fmt:"fixed"` // Player X as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
+	Y           int32  `fmt:"fixed"` // Player X as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
+	Z           int32  `fmt:"fixed"` // Player X as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
+	Yaw         int8   `fmt:"angle"` // Player rotation as a packed byte
+	Pitch       int8   `fmt:"angle"` // Player rotation as a packet byte
 	CurrentItem int16  // The item the player is currently holding. Note that this should be 0 for "no item", unlike -1 used in other packets. A negative value crashes clients.
 	Values      Metadata
 }
@@ -115,11 +115,11 @@ type CollectItem struct {
 type SpawnObject struct {
 	EntityID uint  // Entity ID of the object
 	Type     int8  // The type of object (See [[Entities#Objects|Objects]])
-	X        int32 // X position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
-	Y        int32 // Y position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
-	Z        int32 // Z position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
-	Pitch    int8  // The pitch in steps of 2p/256
-	Yaw      int8  // The yaw in steps of 2p/256
+	X        int32 `fmt:"fixed"` // X position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
+	Y        int32 `fmt:"fixed"` // Y position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
+	Z        int32 `fmt:"fixed"` // Z position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
+	Pitch    int8  `fmt:"angle"` // The pitch in steps of 2p/256
+	Yaw      int8  `fmt:"angle"` // The yaw in steps of 2p/256
 	Data     ObjectData
 }
 
@@ -127,15 +127,15 @@ type SpawnObject struct {
 type SpawnMob struct {
 	EntityID  uint  // Entity's ID
 	Type      uint8 // The type of mob. See [[Entities#Mobs|Mobs]]
-	X         int32 // X position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
-	Y         int32 // Y position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
-	Z         int32 // Z position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
-	Pitch     int8  // The pitch in steps of 2p/256
-	HeadPitch int8  // The pitch in steps of 2p/256
-	Yaw       int8  // The yaw in steps of 2p/256
-	VelocityX int16
-	VelocityY int16
-	VelocityZ int16
+	X         int32 `fmt:"fixed"` // X position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
+	Y         int32 `fmt:"fixed"` // Y position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
+	Z         int32 `fmt:"fixed"` // Z position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
+	Pitch     int8  `fmt:"angle"` // The pitch in steps of 2p/256
+	HeadPitch int8  `fmt:"angle"` // The pitch in steps of 2p/256
+	Yaw       int8  `fmt:"angle"` // The yaw in steps of 2p/256
+	VelocityX int16 `fmt:"velocity"`
+	VelocityY int16 `fmt:"velocity"`
+	VelocityZ int16 `fmt:"velocity"`
 	Values    Metadata
 }
 
@@ -152,18 +152,18 @@ type SpawnPainting struct {
 // 0x11 = Spawn Experience Orb
 type SpawnExperienceOrb struct {
 	EntityID uint  // Entity's ID
-	X        int32 // X position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
-	Y        int32 // Y position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
-	Z        int32 // Z position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
+	X        int32 `fmt:"fixed"` // X position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
+	Y        int32 `fmt:"fixed"` // Y position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
+	Z        int32 `fmt:"fixed"` // Z position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
 	Count    int16 // The amount of experience this orb will reward once collected
 }
 
 // 0x12 = Entity Velocity
 type EntityVelocity struct {
 	EntityID  int32 // Entity's ID
-	VelocityX int16 // Velocity on the X axis
-	VelocityY int16 // Velocity on the Y axis
-	VelocityZ int16 // Velocity on the Z axis
+	VelocityX int16 `fmt:"velocity"` // Velocity on the X axis
+	VelocityY int16 `fmt:"velocity"` // Velocity on the Y axis
+	VelocityZ int16 `fmt:"velocity"` // Velocity on the Z axis
 }
 
 // 0x13 = Destroy Entities
@@ -179,42 +179,42 @@ type Entity struct {
 // 0x15 = Entity Relative Move
 type EntityRelativeMove struct {
 	EntityID int32 // Entity's ID
-	DX       int8  // Change in X position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
-	DY       int8  // Change in Y position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
-	DZ       int8  // Change in Z position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
+	DX       int8  `fmt:"fixed"` // Change in X position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
+	DY       int8  `fmt:"fixed"` // Change in Y position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
+	DZ       int8  `fmt:"fixed"` // Change in Z position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
 }
 
 // 0x16 = Entity Look
 type EntityLook struct {
 	EntityID int32 // Entity's ID
-	Yaw      int8  // The X Axis rotation as a fraction of 360
-	Pitch    int8  // The Y Axis rotation as a fraction of 360
+	Yaw      int8  `fmt:"angle"` // The X Axis rotation as a fraction of 360
+	Pitch    int8  `fmt:"angle"` // The Y Axis rotation as a fraction of 360
 }
 
 // 0x17 = Entity Look and Relative Move
 type EntityLookAndRelativeMove struct {
 	EntityID int32 // Entity's ID
-	DX       int8  // Change in X position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
-	DY       int8  // Change in Y position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
-	DZ       int8  // Change in Z position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
-	Yaw      int8  // The X Axis rotation as a fraction of 360
-	Pitch    int8  // The Y Axis rotation as a fraction of 360
+	DX       int8  `fmt:"fixed"` // Change in X position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
+	DY       int8  `fmt:"fixed"` // Change in Y position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
+	DZ       int8  `fmt:"fixed"` // Change in Z position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
+	Yaw      int8  `fmt:"angle"` // The X Axis rotation as a fraction of 360
+	Pitch    int8  `fmt:"angle"` // The Y Axis rotation as a fraction of 360
 }
 
 // 0x18 = Entity Teleport
 type EntityTeleport struct {
 	EntityID int32 // Entity's ID
-	X        int32 // X position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
-	Y        int32 // Y position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
-	Z        int32 // Z position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
-	Yaw      int8  // The X Axis rotation as a fraction of 360
-	Pitch    int8  // The Y Axis rotation as a fraction of 360
+	X        int32 `fmt:"fixed"` // X position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
+	Y        int32 `fmt:"fixed"` // Y position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
+	Z        int32 `fmt:"fixed"` // Z position as a [[Data_Types#Fixed-point_numbers|Fixed-Point number]]
+	Yaw      int8  `fmt:"angle"` // The X Axis rotation as a fraction of 360
+	Pitch    int8  `fmt:"angle"` // The Y Axis rotation as a fraction of 360
 }
 
 // 0x19 = Entity Head Look
 type EntityHeadLook struct {
 	EntityID int32 // Entity's ID
-	HeadYaw  int8  // Head yaw in steps of 2p/256
+	HeadYaw  int8  `fmt:"angle"` // Head yaw in steps of 2p/256
 }
 
 // 0x1A = Entity Status
@@ -313,9 +313,9 @@ type BlockBreakAnimation struct {
 type MapChunkBulk struct {
 	ChunkColumnCount int16 // The number of chunk in this packet
 	//DataLength            int32      // The size of the data field
-	SkyLightSent bool   // Whether or not the chunk data contains a light nibble array. This is true in the main world, false in the end + nether
-	Data         []byte // Compressed chunk data
-	Meta         MapChunkBulkMeta
+	SkyLightSent bool               // Whether or not the chunk data contains a light nibble array. This is true in the main world, false in the end + nether
+	Data         []byte             // Compressed chunk data
+	Meta         []MapChunkBulkMeta // ChunkColumnCount entries
 }
 
 func (p *MapChunkBulk) MarshalPacket(k *Coder) {
@@ -323,7 +323,9 @@ func (p *MapChunkBulk) MarshalPacket(k *Coder) {
 	k.PutUint32(uint32(len(p.Data)))
 	k.PutBool(p.SkyLightSent)
 	copy(k.Get(len(p.Data)), p.Data)
-	mapChunkBulkMetaCoder.rf(reflect.ValueOf(&p.Meta).Elem(), k)
+	for i := range p.Meta {
+		mapChunkBulkMetaCoder.wf(k, reflect.ValueOf(p.Meta[i]))
+	}
 }
 
 func (p *MapChunkBulk) UnmarshalPacket(k *Coder) {
@@ -332,7 +334,10 @@ func (p *MapChunkBulk) UnmarshalPacket(k *Coder) {
 	p.SkyLightSent = k.Bool()
 	p.Data = make([]byte, dlen)
 	copy(p.Data, k.Get(dlen))
-	mapChunkBulkMetaCoder.wf(k, reflect.ValueOf(p.Meta))
+	p.Meta = make([]MapChunkBulkMeta, p.ChunkColumnCount)
+	for i := range p.Meta {
+		mapChunkBulkMetaCoder.rf(reflect.ValueOf(&p.Meta[i]).Elem(), k)
+	}
 }
 
 // 0x27 = Explosion
@@ -360,9 +365,9 @@ type Effect struct {
 // 0x29 = Sound Effect
 type SoundEffect struct {
 	SoundName       string
-	EffectPositionX int32   // Effect X multiplied by 8
-	EffectPositionY int32   // Effect Y multiplied by 8
-	EffectPositionZ int32   // Effect Z multiplied by 8
+	EffectPositionX int32   `fmt:"div=8"` // Effect X multiplied by 8
+	EffectPositionY int32   `fmt:"div=8"` // Effect Y multiplied by 8
+	EffectPositionZ int32   `fmt:"div=8"` // Effect Z multiplied by 8
 	Volume          float32 // 1 is 100%, can be more
 	Pitch           uint8   // 63 is 100%, can be more
 }
@@ -390,9 +395,9 @@ type ChangeGameState struct {
 type SpawnGlobalEntity struct {
 	EntityID uint  // The entity ID of the thunderbolt
 	Type     int8  // The global entity type, currently always 1 for thunderbolt.
-	X        int32 // Thunderbolt X a [[Data_Types#Fixed-point_numbers|fixed-point number]]
-	Y        int32 // Thunderbolt Y a [[Data_Types#Fixed-point_numbers|fixed-point number]]
-	Z        int32 // Thunderbolt Z a [[Data_Types#Fixed-point_numbers|fixed-point number]]
+	X        int32 `fmt:"fixed"` // Thunderbolt X a [[Data_Types#Fixed-point_numbers|fixed-point number]]
+	Y        int32 `fmt:"fixed"` // Thunderbolt Y a [[Data_Types#Fixed-point_numbers|fixed-point number]]
+	Z        int32 `fmt:"fixed"` // Thunderbolt Z a [[Data_Types#Fixed-point_numbers|fixed-point number]]
 }
 
 // 0x2D = Open Window
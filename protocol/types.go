@@ -52,9 +52,9 @@ func (s *Slot) UnmarshalPacket(k *Coder) {
 type ObjectData struct {
 	Data     uint32
 	HasSpeed bool
-	SpeedX   int16
-	SpeedY   int16
-	SpeedZ   int16
+	SpeedX   int16 `fmt:"velocity"`
+	SpeedY   int16 `fmt:"velocity"`
+	SpeedZ   int16 `fmt:"velocity"`
 }
 
 func (o *ObjectData) MarshalPacket(k *Coder) {
@@ -124,8 +124,9 @@ func (d Metadata) MarshalPacket(k *Coder) {
 	k.PutUint8(0x7f)
 }
 
-func (d Metadata) UnmarshalPacket(k *Coder) {
-	d = make(Metadata)
+func (pd *Metadata) UnmarshalPacket(k *Coder) {
+	d := make(Metadata)
+	*pd = d
 	for k.Len() > 0 {
 		b := k.Uint8()
 		if b == 0x7f {
@@ -151,7 +152,7 @@ func (d Metadata) UnmarshalPacket(k *Coder) {
 			p := new(XYZint)
 			p.X = int(k.Int32())
 			p.Y = int(k.Int32())
-			p.X = int(k.Int32())
+			p.Z = int(k.Int32())
 			d[idx] = p
 		}
 	}
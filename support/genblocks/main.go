@@ -0,0 +1,288 @@
+// Command genblocks generates the block table of package blocks
+// from the data file blocks.txt.
+//
+// Usage:
+//
+//	genblocks [-o table.go] [blocks.txt]
+//
+// The table is written to the standard output if -o is not given.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+)
+
+type block struct {
+	id         int
+	name       string
+	display    string
+	hardness   string
+	tool       string
+	level      int
+	light      int
+	flags      string
+	shape      string
+	box        []string
+	variants   map[int]string
+	maxVariant int
+}
+
+var tools = map[string]string{
+	"none":    "ToolNone",
+	"pickaxe": "ToolPickaxe",
+	"shovel":  "ToolShovel",
+	"axe":     "ToolAxe",
+	"sword":   "ToolSword",
+	"shears":  "ToolShears",
+}
+
+var shapes = map[string]string{
+	"full":     "ShapeFull",
+	"none":     "ShapeNone",
+	"slab":     "ShapeSlab",
+	"stairs":   "ShapeStairs",
+	"ladder":   "ShapeLadder",
+	"snow":     "ShapeSnow",
+	"trapdoor": "ShapeTrapdoor",
+	"gate":     "ShapeGate",
+	"door":     "ShapeDoor",
+	"cake":     "ShapeCake",
+}
+
+var colors = []string{
+	"White", "Orange", "Magenta", "Light Blue", "Yellow", "Lime", "Pink", "Gray",
+	"Light Gray", "Cyan", "Purple", "Blue", "Brown", "Green", "Red", "Black",
+}
+
+// initialisms are names not converted to identifiers word by word.
+var initialisms = map[string]string{
+	"tnt": "TNT",
+}
+
+func main() {
+	out := flag.String("o", "", "output file")
+	flag.Parse()
+	fn := "blocks.txt"
+	if flag.NArg() > 0 {
+		fn = flag.Arg(0)
+	}
+	f, err := os.Open(fn)
+	check(err)
+	defer f.Close()
+	v, err := parse(f)
+	if err != nil {
+		check(fmt.Errorf("%s:%v", fn, err))
+	}
+	src, err := format.Source(generate(fn, v))
+	check(err)
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	check(ioutil.WriteFile(*out, src, 0644))
+}
+
+func check(err error) {
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "genblocks:", err)
+		os.Exit(1)
+	}
+}
+
+func parse(r io.Reader) ([]*block, error) {
+	var v []*block
+	seen := make(map[int]bool)
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := s.Text()
+		if i := strings.Index(line, "#"); i >= 0 && !strings.Contains(line[:i], `"`) {
+			line = line[:i]
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		fields, display, err := split(line)
+		if err != nil {
+			return nil, fmt.Errorf("%d: %v", n, err)
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			if len(v) == 0 {
+				return nil, fmt.Errorf("%d: variant without block", n)
+			}
+			if err := v[len(v)-1].variant(fields, display); err != nil {
+				return nil, fmt.Errorf("%d: %v", n, err)
+			}
+			continue
+		}
+		b, err := parseBlock(fields, display)
+		if err != nil {
+			return nil, fmt.Errorf("%d: %v", n, err)
+		}
+		if seen[b.id] {
+			return nil, fmt.Errorf("%d: duplicate block id %d", n, b.id)
+		}
+		seen[b.id] = true
+		v = append(v, b)
+	}
+	return v, s.Err()
+}
+
+// split returns the fields of line before the quoted display name,
+// and the display name.
+func split(line string) (fields []string, display string, err error) {
+	i := strings.Index(line, `"`)
+	if i < 0 {
+		return strings.Fields(line), "", nil
+	}
+	display, err = strconv.Unquote(strings.TrimSpace(line[i:]))
+	return strings.Fields(line[:i]), display, err
+}
+
+func parseBlock(f []string, display string) (*block, error) {
+	if len(f) != 8 || display == "" {
+		return nil, fmt.Errorf("want 8 fields and a display name, got %d fields", len(f))
+	}
+	b := &block{name: f[1], display: display, hardness: f[2], flags: f[6]}
+	var err error
+	if b.id, err = strconv.Atoi(f[0]); err != nil {
+		return nil, err
+	}
+	if _, err = strconv.ParseFloat(b.hardness, 64); err != nil {
+		return nil, err
+	}
+	var ok bool
+	if b.tool, ok = tools[f[3]]; !ok {
+		return nil, fmt.Errorf("unknown tool %q", f[3])
+	}
+	b.level = -1
+	if f[4] != "-" {
+		if b.level, err = strconv.Atoi(f[4]); err != nil {
+			return nil, err
+		}
+	}
+	if b.light, err = strconv.Atoi(f[5]); err != nil || b.light < 0 || b.light > 15 {
+		return nil, fmt.Errorf("invalid light %q", f[5])
+	}
+	if strings.Trim(b.flags, "-STRI") != "" {
+		return nil, fmt.Errorf("invalid flags %q", b.flags)
+	}
+	if b.shape, ok = shapes[f[7]]; !ok {
+		b.shape = "ShapeBox"
+		b.box = strings.Split(f[7], ",")
+		if len(b.box) != 6 {
+			return nil, fmt.Errorf("invalid shape %q", f[7])
+		}
+		for i, s := range b.box {
+			x, err := strconv.ParseFloat(s, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid shape %q", f[7])
+			}
+			b.box[i] = strconv.FormatFloat(x/16, 'g', -1, 64)
+		}
+	}
+	return b, nil
+}
+
+func (b *block) variant(f []string, display string) error {
+	if b.variants == nil {
+		b.variants = make(map[int]string)
+	}
+	if len(f) == 1 && f[0] == "colors" && display == "" {
+		for i, c := range colors {
+			b.variants[i] = c + " " + b.display
+		}
+		b.maxVariant = 15
+		return nil
+	}
+	if len(f) != 1 || display == "" {
+		return fmt.Errorf("want metadata and a display name")
+	}
+	m, err := strconv.Atoi(f[0])
+	if err != nil || m < 0 || m > 15 {
+		return fmt.Errorf("invalid metadata %q", f[0])
+	}
+	b.variants[m] = display
+	if m > b.maxVariant {
+		b.maxVariant = m
+	}
+	return nil
+}
+
+func generate(fn string, v []*block) []byte {
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "// Code generated by genblocks from %s; DO NOT EDIT.\n\n", fn)
+	fmt.Fprintln(buf, "package blocks")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "// Block ids.")
+	fmt.Fprintln(buf, "const (")
+	for _, b := range v {
+		fmt.Fprintf(buf, "%s = %d\n", ident(b.name), b.id)
+	}
+	fmt.Fprintln(buf, ")")
+	fmt.Fprintln(buf)
+	fmt.Fprintln(buf, "var table = [...]Block{")
+	for _, b := range v {
+		id := ident(b.name)
+		fmt.Fprintf(buf, "%s: {\nID: %s,\nName: %q,\nDisplayName: %q,\n", id, id, b.name, b.display)
+		if b.hardness != "0" {
+			fmt.Fprintf(buf, "Hardness: %s,\n", b.hardness)
+		}
+		if b.tool != "ToolNone" {
+			fmt.Fprintf(buf, "Tool: %s,\n", b.tool)
+		}
+		fmt.Fprintf(buf, "Level: %d,\n", b.level)
+		if b.light != 0 {
+			fmt.Fprintf(buf, "Light: %d,\n", b.light)
+		}
+		for _, f := range []struct {
+			c    string
+			name string
+		}{{"S", "Solid"}, {"T", "Transparent"}, {"R", "Replaceable"}, {"I", "Interactive"}} {
+			if strings.Contains(b.flags, f.c) {
+				fmt.Fprintf(buf, "%s: true,\n", f.name)
+			}
+		}
+		if b.shape != "ShapeFull" {
+			fmt.Fprintf(buf, "Shape: %s,\n", b.shape)
+		}
+		if b.box != nil {
+			fmt.Fprintf(buf, "box: []Box{{Min: [3]float64{%s}, Max: [3]float64{%s}}},\n",
+				strings.Join(b.box[:3], ", "), strings.Join(b.box[3:], ", "))
+		}
+		if b.variants != nil {
+			mask := 1
+			for mask < b.maxVariant {
+				mask = mask<<1 | 1
+			}
+			fmt.Fprintf(buf, "mask: %d,\nvariants: []string{\n", mask)
+			for m := 0; m <= b.maxVariant; m++ {
+				fmt.Fprintf(buf, "%q,\n", b.variants[m])
+			}
+			fmt.Fprintln(buf, "},")
+		}
+		fmt.Fprintln(buf, "},")
+	}
+	fmt.Fprintln(buf, "}")
+	return buf.Bytes()
+}
+
+// ident returns the Go identifier of the block named name.
+func ident(name string) string {
+	if s, ok := initialisms[name]; ok {
+		return s
+	}
+	w := strings.Split(name, "_")
+	for i, s := range w {
+		w[i] = strings.ToUpper(s[:1]) + s[1:]
+	}
+	return strings.Join(w, "")
+}
@@ -0,0 +1,187 @@
+// Package world decodes and tracks the chunks of a Minecraft world.
+package world
+
+import (
+	"bytes"
+	"compress/zlib"
+	"errors"
+	"io"
+	"io/ioutil"
+)
+
+const (
+	sectionBlocks = 16 * 16 * 16
+	nibbleLen     = sectionBlocks / 2
+	biomesLen     = 16 * 16
+
+	// maxColumnLen is the size of a column with all arrays present.
+	maxColumnLen = 16*(sectionBlocks+4*nibbleLen) + biomesLen
+)
+
+var (
+	ErrChunkSize = errors.New("world: chunk data size invalid")
+)
+
+// Section is a 16x16x16 section of a chunk column.
+// Arrays are indexed by y<<8 | z<<4 | x, nibble arrays
+// have even indices in the low nibble.
+type Section struct {
+	Blocks     [sectionBlocks]byte // low 8 bits of block ids
+	Add        [nibbleLen]byte     // high 4 bits of block ids
+	Meta       [nibbleLen]byte
+	BlockLight [nibbleLen]byte
+	SkyLight   [nibbleLen]byte
+}
+
+func sectionIndex(x, y, z int) int {
+	return (y&15)<<8 | (z&15)<<4 | x&15
+}
+
+func nibble(a []byte, i int) int {
+	return int(a[i>>1]>>(uint(i&1)*4)) & 15
+}
+
+func setNibble(a []byte, i, v int) {
+	s := uint(i&1) * 4
+	a[i>>1] = a[i>>1]&^(15<<s) | byte(v&15)<<s
+}
+
+// Block returns the id and metadata of the block at x, y, z
+// within the section. Only the low 4 bits of coordinates are used.
+func (s *Section) Block(x, y, z int) (id, meta int) {
+	i := sectionIndex(x, y, z)
+	return int(s.Blocks[i]) | nibble(s.Add[:], i)<<8, nibble(s.Meta[:], i)
+}
+
+// SetBlock sets the id and metadata of the block at x, y, z.
+func (s *Section) SetBlock(x, y, z, id, meta int) {
+	i := sectionIndex(x, y, z)
+	s.Blocks[i] = byte(id)
+	setNibble(s.Add[:], i, id>>8)
+	setNibble(s.Meta[:], i, meta)
+}
+
+// Empty reports if all blocks of s are air.
+func (s *Section) Empty() bool {
+	for _, b := range s.Blocks {
+		if b != 0 {
+			return false
+		}
+	}
+	for _, b := range s.Add {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Column is a 16 block wide column of 16 sections, as sent by the server.
+//
+// A column with Full set and zero Bitmap means the client
+// should unload the column.
+type Column struct {
+	X, Z     int32        // column coordinates, block coordinates divided by 16
+	Full     bool         // the column replaces any previous one, including biomes
+	Bitmap   uint16       // sections sent
+	Sections [16]*Section // sections sent, nil for others
+	Biomes   []byte       // biome ids indexed by z<<4 | x, nil if not Full
+}
+
+// Block returns the id and metadata of the block at x, y, z, where x and z
+// are within the column. Blocks in sections not present are air.
+func (c *Column) Block(x, y, z int) (id, meta int) {
+	if y < 0 || y >= 256 || c.Sections[y>>4] == nil {
+		return 0, 0
+	}
+	return c.Sections[y>>4].Block(x, y, z)
+}
+
+// SetBlock sets the id and metadata of the block at x, y, z, where x and z
+// are within the column. Sections are allocated as needed.
+func (c *Column) SetBlock(x, y, z, id, meta int) {
+	if y < 0 || y >= 256 {
+		return
+	}
+	s := c.Sections[y>>4]
+	if s == nil {
+		if id == 0 {
+			return
+		}
+		s = new(Section)
+		c.Sections[y>>4] = s
+		c.Bitmap |= 1 << uint(y>>4)
+	}
+	s.SetBlock(x, y, z, id, meta)
+}
+
+func bitCount(m uint16) int {
+	n := 0
+	for ; m != 0; m &= m - 1 {
+		n++
+	}
+	return n
+}
+
+// columnLen returns the size of the data of a column.
+func columnLen(bitmap, addmap uint16, skylight, biomes bool) int {
+	n, a := bitCount(bitmap), bitCount(addmap&bitmap)
+	l := n*(sectionBlocks+2*nibbleLen) + a*nibbleLen
+	if skylight {
+		l += n * nibbleLen
+	}
+	if biomes {
+		l += biomesLen
+	}
+	return l
+}
+
+// parseColumn fills the sections in bitmap from b. Arrays follow each
+// other by type: block ids for all sections, then metadata, block light,
+// sky light if present, block id high bits and biomes if c.Full is set.
+func parseColumn(c *Column, b []byte, bitmap, addmap uint16, skylight bool) {
+	c.Bitmap = bitmap
+	var v []*Section
+	for i := uint(0); i < 16; i++ {
+		if bitmap&(1<<i) != 0 {
+			s := new(Section)
+			c.Sections[i] = s
+			v = append(v, s)
+		}
+	}
+	for _, s := range v {
+		b = b[copy(s.Blocks[:], b):]
+	}
+	for _, s := range v {
+		b = b[copy(s.Meta[:], b):]
+	}
+	for _, s := range v {
+		b = b[copy(s.BlockLight[:], b):]
+	}
+	if skylight {
+		for _, s := range v {
+			b = b[copy(s.SkyLight[:], b):]
+		}
+	}
+	for i := uint(0); i < 16; i++ {
+		if s := c.Sections[i]; s != nil && addmap&(1<<i) != 0 {
+			b = b[copy(s.Add[:], b):]
+		}
+	}
+	if c.Full {
+		c.Biomes = append([]byte(nil), b[:biomesLen]...)
+	}
+}
+
+// inflate returns at most max+1 bytes of the zlib compressed b.
+func inflate(b []byte, max int) ([]byte, error) {
+	if len(b) == 0 {
+		return nil, nil
+	}
+	r, err := zlib.NewReader(bytes.NewReader(b))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return ioutil.ReadAll(io.LimitReader(r, int64(max)+1))
+}
@@ -0,0 +1,80 @@
+package world
+
+import (
+	proto "github.com/tajtiattila/mctoy/protocol"
+)
+
+// ParseChunkData returns the column sent in p. Whether sky light is
+// included is deduced from the size of the data.
+func ParseChunkData(p *proto.ChunkData) (*Column, error) {
+	c := &Column{X: p.ChunkX, Z: p.ChunkZ, Full: p.GroundUpContinuous}
+	bitmap, addmap := uint16(p.PrimaryBitMap), uint16(p.AddBitMap)
+	b, err := inflate(p.CompressedData, maxColumnLen)
+	if err != nil {
+		return nil, err
+	}
+	if c.Full && bitmap == 0 && len(b) < biomesLen {
+		// unload column
+		return c, nil
+	}
+	skylight := false
+	switch len(b) {
+	case columnLen(bitmap, addmap, false, c.Full):
+	case columnLen(bitmap, addmap, true, c.Full):
+		skylight = true
+	default:
+		return nil, ErrChunkSize
+	}
+	parseColumn(c, b, bitmap, addmap, skylight)
+	return c, nil
+}
+
+// ParseMapChunkBulk returns the columns sent in p.
+func ParseMapChunkBulk(p *proto.MapChunkBulk) ([]*Column, error) {
+	n := 0
+	for _, m := range p.Meta {
+		n += columnLen(m.PrimaryBitmap, m.AddBitmap, p.SkyLightSent, true)
+	}
+	b, err := inflate(p.Data, n)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != n {
+		return nil, ErrChunkSize
+	}
+	v := make([]*Column, len(p.Meta))
+	for i, m := range p.Meta {
+		c := &Column{X: m.ChunkX, Z: m.ChunkZ, Full: true}
+		parseColumn(c, b, m.PrimaryBitmap, m.AddBitmap, p.SkyLightSent)
+		b = b[columnLen(m.PrimaryBitmap, m.AddBitmap, p.SkyLightSent, true):]
+		v[i] = c
+	}
+	return v, nil
+}
+
+// Chunks is a chunk packet with its columns parsed.
+type Chunks struct {
+	Packet  interface{} // *proto.ChunkData or *proto.MapChunkBulk
+	Columns []*Column
+}
+
+// Process replaces ChunkData and MapChunkBulk packets with Chunks,
+// and returns other packets unchanged. Inflating chunks is expensive,
+// Process is meant to be used as the Process function of a net.Pipeline.
+func Process(p interface{}) (interface{}, error) {
+	switch x := p.(type) {
+	case *proto.ChunkData:
+		c, err := ParseChunkData(x)
+		if err != nil {
+			return nil, err
+		}
+		return &Chunks{Packet: p, Columns: []*Column{c}}, nil
+	case *proto.MapChunkBulk:
+		v, err := ParseMapChunkBulk(x)
+		if err != nil {
+			return nil, err
+		}
+		return &Chunks{Packet: p, Columns: v}, nil
+	}
+	return p, nil
+}
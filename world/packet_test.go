@@ -0,0 +1,122 @@
+package world
+
+import (
+	"bytes"
+	"compress/zlib"
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"reflect"
+	"testing"
+)
+
+func deflate(b []byte) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write(b)
+	w.Close()
+	return buf.Bytes()
+}
+
+// testColumn returns a column with blocks in sections 0 and 2,
+// and its data.
+func testColumn(x, z int32, skylight bool) (*Column, []byte) {
+	c := &Column{X: x, Z: z, Full: true, Biomes: make([]byte, biomesLen)}
+	c.SetBlock(1, 2, 3, 1, 0)
+	c.SetBlock(15, 40, 0, 0x135, 7)
+	for i := range c.Biomes {
+		c.Biomes[i] = byte(i % 23)
+	}
+	s0, s2 := c.Sections[0], c.Sections[2]
+	s0.BlockLight[5], s2.SkyLight[9] = 0x12, 0xf0
+	if !skylight {
+		s2.SkyLight[9] = 0
+	}
+	var b []byte
+	for _, s := range []*Section{s0, s2} {
+		b = append(b, s.Blocks[:]...)
+	}
+	for _, s := range []*Section{s0, s2} {
+		b = append(b, s.Meta[:]...)
+	}
+	for _, s := range []*Section{s0, s2} {
+		b = append(b, s.BlockLight[:]...)
+	}
+	if skylight {
+		for _, s := range []*Section{s0, s2} {
+			b = append(b, s.SkyLight[:]...)
+		}
+	}
+	b = append(b, s2.Add[:]...)
+	b = append(b, c.Biomes...)
+	return c, b
+}
+
+func TestParseChunkData(t *testing.T) {
+	for _, skylight := range []bool{false, true} {
+		want, b := testColumn(3, -4, skylight)
+		got, err := ParseChunkData(&proto.ChunkData{
+			ChunkX:             3,
+			ChunkZ:             -4,
+			GroundUpContinuous: true,
+			PrimaryBitMap:      5,
+			AddBitMap:          4,
+			CompressedData:     deflate(b),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("skylight %v: column mismatch", skylight)
+		}
+		if id, meta := got.Block(15, 40, 0); id != 0x135 || meta != 7 {
+			t.Errorf("got block %x:%d", id, meta)
+		}
+	}
+
+	c, err := ParseChunkData(&proto.ChunkData{ChunkX: 1, GroundUpContinuous: true})
+	if err != nil || c.Bitmap != 0 || !c.Full {
+		t.Errorf("unload column: %+v %v", c, err)
+	}
+	if _, err = ParseChunkData(&proto.ChunkData{PrimaryBitMap: 1, CompressedData: deflate(make([]byte, 100))}); err != ErrChunkSize {
+		t.Errorf("got %v, want ErrChunkSize", err)
+	}
+}
+
+func TestParseMapChunkBulk(t *testing.T) {
+	c1, b1 := testColumn(0, 0, true)
+	c2, b2 := testColumn(1, 0, true)
+	p := &proto.MapChunkBulk{
+		ChunkColumnCount: 2,
+		SkyLightSent:     true,
+		Data:             deflate(append(b1, b2...)),
+		Meta: []proto.MapChunkBulkMeta{
+			{ChunkX: 0, ChunkZ: 0, PrimaryBitmap: 5, AddBitmap: 4},
+			{ChunkX: 1, ChunkZ: 0, PrimaryBitmap: 5, AddBitmap: 4},
+		},
+	}
+
+	// round trip through the protocol
+	buf := make([]byte, 1<<16)
+	n, err := proto.GetHostState(proto.Server, proto.StatePlay).Encode(buf, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := proto.GetHostState(proto.Client, proto.StatePlay).Decode(buf[:n])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(p, q) {
+		t.Fatalf("packet round trip failed:\n%+v\n%+v", p, q)
+	}
+
+	x, err := Process(q)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ch, ok := x.(*Chunks)
+	if !ok || ch.Packet != q {
+		t.Fatalf("got %T, want *Chunks", x)
+	}
+	if !reflect.DeepEqual(ch.Columns, []*Column{c1, c2}) {
+		t.Error("columns mismatch")
+	}
+}
@@ -0,0 +1,140 @@
+package world
+
+import (
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"sync"
+)
+
+// World is the set of columns loaded by a client. It is safe
+// for concurrent use.
+type World struct {
+	mtx  sync.RWMutex
+	cols map[colKey]*Column
+}
+
+type colKey struct{ x, z int32 }
+
+// New returns an empty World.
+func New() *World {
+	return &World{cols: make(map[colKey]*Column)}
+}
+
+// Reset unloads all columns, eg. when the player changes dimension.
+func (w *World) Reset() {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	w.cols = make(map[colKey]*Column)
+}
+
+// Column returns the column at column coordinates x, z,
+// or nil if it is not loaded.
+func (w *World) Column(x, z int32) *Column {
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+	return w.cols[colKey{x, z}]
+}
+
+// Len returns the number of columns loaded.
+func (w *World) Len() int {
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+	return len(w.cols)
+}
+
+// Loaded reports if the column with the block at x, z is loaded.
+func (w *World) Loaded(x, z int) bool {
+	return w.Column(int32(x>>4), int32(z>>4)) != nil
+}
+
+// Block returns the id and metadata of the block at x, y, z.
+// Blocks in columns not loaded are air.
+func (w *World) Block(x, y, z int) (id, meta int) {
+	w.mtx.RLock()
+	defer w.mtx.RUnlock()
+	c := w.cols[colKey{int32(x >> 4), int32(z >> 4)}]
+	if c == nil {
+		return 0, 0
+	}
+	return c.Block(x&15, y, z&15)
+}
+
+// SetBlock sets the block at x, y, z if its column is loaded.
+func (w *World) SetBlock(x, y, z, id, meta int) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if c := w.cols[colKey{int32(x >> 4), int32(z >> 4)}]; c != nil {
+		c.SetBlock(x&15, y, z&15, id, meta)
+	}
+}
+
+// Update adds column c to the world. A Full column replaces the previous
+// one, or unloads it if it has no sections. Sections of other columns
+// replace those of the loaded column.
+func (w *World) Update(c *Column) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	k := colKey{c.X, c.Z}
+	switch old := w.cols[k]; {
+	case c.Full && c.Bitmap == 0:
+		delete(w.cols, k)
+	case c.Full || old == nil:
+		w.cols[k] = c
+	default:
+		for i, s := range c.Sections {
+			if c.Bitmap&(1<<uint(i)) != 0 {
+				old.Sections[i] = s
+			}
+		}
+		old.Bitmap |= c.Bitmap
+	}
+}
+
+// Apply updates the world with packet p, and reports if p had any effect.
+// It handles Chunks, ChunkData, MapChunkBulk, BlockChange and
+// MultiBlockChange packets.
+func (w *World) Apply(p interface{}) (bool, error) {
+	switch x := p.(type) {
+	case *Chunks:
+		for _, c := range x.Columns {
+			w.Update(c)
+		}
+	case *proto.ChunkData, *proto.MapChunkBulk:
+		ch, err := Process(p)
+		if err != nil {
+			return false, err
+		}
+		return w.Apply(ch)
+	case *proto.BlockChange, *proto.MultiBlockChange:
+		for _, b := range BlockChanges(p) {
+			w.SetBlock(b.X, b.Y, b.Z, b.ID, b.Meta)
+		}
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// BlockChange is a block set by the server.
+type BlockChange struct {
+	X, Y, Z  int
+	ID, Meta int
+}
+
+// BlockChanges returns the blocks changed by a BlockChange or
+// MultiBlockChange packet, and nil for other packets.
+func BlockChanges(p interface{}) []BlockChange {
+	switch x := p.(type) {
+	case *proto.BlockChange:
+		return []BlockChange{{int(x.X), int(x.Y), int(x.Z), int(x.BlockType), int(x.BlockData)}}
+	case *proto.MultiBlockChange:
+		bx, bz := int(x.ChunkX)<<4, int(x.ChunkZ)<<4
+		v := make([]BlockChange, len(x.Records))
+		for i, r := range x.Records {
+			// xxxxzzzz yyyyyyyy iiiiiiii iiiimmmm
+			v[i] = BlockChange{bx + int(r>>28), int(r >> 16 & 0xff), bz + int(r>>24&15),
+				int(r >> 4 & 0xfff), int(r & 15)}
+		}
+		return v
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package world
+
+import (
+	proto "github.com/tajtiattila/mctoy/protocol"
+	"testing"
+)
+
+func TestWorld(t *testing.T) {
+	w := New()
+	c, _ := testColumn(-1, 2, true)
+	w.Update(c)
+	if !w.Loaded(-16, 32) || !w.Loaded(-1, 47) || w.Loaded(0, 32) {
+		t.Error("column not loaded where expected")
+	}
+	if id, meta := w.Block(-15, 2, 35); id != 1 || meta != 0 {
+		t.Errorf("block %d:%d, want 1:0", id, meta)
+	}
+	if id, meta := w.Block(-1, 40, 32); id != 0x135 || meta != 7 {
+		t.Errorf("block %d:%d, want 0x135:7", id, meta)
+	}
+
+	w.Apply(&proto.BlockChange{X: -15, Y: 2, Z: 35, BlockType: 4, BlockData: 1})
+	if id, meta := w.Block(-15, 2, 35); id != 4 || meta != 1 {
+		t.Errorf("changed block %d:%d, want 4:1", id, meta)
+	}
+	w.Apply(&proto.MultiBlockChange{ChunkX: -1, ChunkZ: 2, RecordCount: 2, Records: []proto.Record{
+		3<<28 | 4<<24 | 100<<16 | 0x12a<<4 | 5,
+		15<<28 | 40<<16,
+	}})
+	if id, meta := w.Block(-13, 100, 36); id != 0x12a || meta != 5 {
+		t.Errorf("multi changed block %d:%d, want 0x12a:5", id, meta)
+	}
+	if id, _ := w.Block(-1, 40, 32); id != 0 {
+		t.Errorf("multi changed block %d, want air", id)
+	}
+
+	part := &Column{X: -1, Z: 2, Bitmap: 1 << 5}
+	part.Sections[5] = new(Section)
+	part.Sections[5].SetBlock(0, 0, 0, 7, 0)
+	w.Update(part)
+	if id, _ := w.Block(-16, 80, 32); id != 7 {
+		t.Errorf("partial update block %d, want 7", id)
+	}
+	if id, _ := w.Block(-13, 100, 36); id != 0x12a {
+		t.Errorf("partial update lost block, got %d", id)
+	}
+
+	w.Update(&Column{X: -1, Z: 2, Full: true})
+	if w.Loaded(-16, 32) || w.Len() != 0 {
+		t.Error("column not unloaded")
+	}
+}